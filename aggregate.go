@@ -0,0 +1,171 @@
+package timeinterval
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// OverlapPolicy decides the value of the parts where labeled values overlap
+type OverlapPolicy int
+
+const (
+	// OverlapSum adds the values up, e.g. CPU limits of the pods of a node
+	OverlapSum OverlapPolicy = iota
+	// OverlapMax takes the largest value
+	OverlapMax
+	// OverlapMin takes the smallest value
+	OverlapMin
+	// OverlapLast takes the value later in the slice, e.g. a value updated by the next one
+	OverlapLast
+)
+
+// TimeWeightedAggregate
+//
+// Aggregate of values holding over TimeIntervals, within a window.
+// The parts of the window without any value are not included, except in Window.
+type TimeWeightedAggregate struct {
+	Window *TimeInterval
+
+	// Covered is the duration of the parts of Window with a value
+	Covered time.Duration
+
+	// Integral is the sum of value × duration in seconds
+	Integral float64
+
+	// Average is Integral / Covered in seconds, NaN if Covered is 0
+	Average float64
+
+	// Min and Max are NaN if Covered is 0
+	Min float64
+	Max float64
+}
+
+// ResolveOverlaps returns the values of values clipped to window, not overlapping each other,
+// where the values overlapping are combined by policy. The result is sorted, and
+// the adjacent parts having the same value are merged.
+// The empty and zero duration elements are ignored, as they hold their values for no time.
+func ResolveOverlaps(values []LabeledTimeInterval[float64], window *TimeInterval, policy OverlapPolicy) []LabeledTimeInterval[float64] {
+	if policy < OverlapSum || policy > OverlapLast {
+		panic(fmt.Sprint("invalid overlap policy: ", policy))
+	}
+	if window == nil {
+		panic("nil argument")
+	}
+
+	type event struct {
+		tp    *TimePoint
+		index int
+		start bool
+	}
+
+	events := []event{}
+	for i, v := range values {
+		ti := v.Interval.Intersection(window)
+		if ti.IsEmpty() || ti.IsZeroDuration() {
+			continue
+		}
+
+		events = append(events, event{tp: ti.start, index: i, start: true}, event{tp: ti.end, index: i})
+	}
+
+	slices.SortFunc(events, func(a, b event) int {
+		switch {
+		case a.tp.Before(b.tp):
+			return -1
+		case a.tp.After(b.tp):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	ret := []LabeledTimeInterval[float64]{}
+
+	// the indices of values holding, in ascending order
+	active := []int{}
+
+	combine := func() float64 {
+		acc := values[active[0]].Label
+		for _, k := range active[1:] {
+			v := values[k].Label
+
+			switch policy {
+			case OverlapSum:
+				acc += v
+			case OverlapMax:
+				acc = math.Max(acc, v)
+			case OverlapMin:
+				acc = math.Min(acc, v)
+			case OverlapLast:
+				acc = v
+			}
+		}
+		return acc
+	}
+
+	for i := 0; i < len(events); {
+		tp := events[i].tp
+
+		for ; i < len(events) && events[i].tp.Equal(tp); i++ {
+			k := events[i].index
+			j, _ := slices.BinarySearch(active, k)
+
+			if events[i].start {
+				active = slices.Insert(active, j, k)
+			} else {
+				active = slices.Delete(active, j, j+1)
+			}
+		}
+
+		if len(active) == 0 || i == len(events) {
+			continue
+		}
+
+		v := combine()
+		next := events[i].tp
+
+		if n := len(ret); n > 0 && ret[n-1].Label == v && ret[n-1].Interval.end.Equal(tp) {
+			ret[n-1].Interval = NewTimeInterval(ret[n-1].Interval.start, next)
+			continue
+		}
+
+		ret = append(ret, LabeledTimeInterval[float64]{
+			Interval: NewTimeInterval(tp, next),
+			Label:    v,
+		})
+	}
+
+	return ret
+}
+
+// TimeWeighted returns TimeWeightedAggregate of values within window, see ResolveOverlaps()
+func TimeWeighted(values []LabeledTimeInterval[float64], window *TimeInterval, policy OverlapPolicy) *TimeWeightedAggregate {
+	ret := &TimeWeightedAggregate{
+		Window:  window,
+		Average: math.NaN(),
+		Min:     math.NaN(),
+		Max:     math.NaN(),
+	}
+
+	for i, v := range ResolveOverlaps(values, window, policy) {
+		d := v.Interval.Duration()
+
+		ret.Covered = addSaturated(ret.Covered, d)
+		ret.Integral += v.Label * d.Seconds()
+
+		if i == 0 {
+			ret.Min, ret.Max = v.Label, v.Label
+			continue
+		}
+		ret.Min = math.Min(ret.Min, v.Label)
+		ret.Max = math.Max(ret.Max, v.Label)
+	}
+
+	if ret.Covered > 0 {
+		ret.Average = ret.Integral / ret.Covered.Seconds()
+	}
+
+	return ret
+}
@@ -0,0 +1,262 @@
+package timeinterval
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var ErrNotReserved = errors.New("timeinterval: interval is not reserved")
+
+// ConflictError
+type ConflictError struct {
+	Resource  string
+	Interval  *TimeInterval
+	Conflicts []*TimeInterval // existing reservations clashing with Interval
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("timeinterval: %s: %v conflicts with %d reservation(s)", e.Resource, e.Interval, len(e.Conflicts))
+}
+
+// Booker
+type Booker struct {
+	mu sync.Mutex
+
+	resources map[string]*bookerResource
+}
+
+type bookerResource struct {
+	capacity     int
+	reservations *TimeIntervalSet
+}
+
+func NewBooker() *Booker {
+	ret := &Booker{
+		resources: map[string]*bookerResource{},
+	}
+
+	return ret
+}
+
+// AddResource registers a resource which can hold up to capacity
+// reservations at the same instant.
+func (b *Booker) AddResource(name string, capacity int) {
+	if capacity < 1 {
+		panic(fmt.Sprint("invalid capacity: ", capacity))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.resources[name]; ok {
+		panic("duplicated resource name: " + name)
+	}
+
+	b.resources[name] = &bookerResource{
+		capacity:     capacity,
+		reservations: NewTimeIntervalSet(),
+	}
+}
+
+func (b *Booker) Capacity(name string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.get(name).capacity
+}
+
+// Reservations returns a sorted copy of the reservations of the resource.
+func (b *Booker) Reservations(name string) *TimeIntervalSet {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.get(name).reservations.Copy()
+}
+
+// Check reports the conflicts Reserve would fail with, without reserving.
+func (b *Booker) Check(name string, ti *TimeInterval) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.get(name).check(name, ti)
+}
+
+// Reserve adds ti to the resource if it does not exceed the capacity
+// of the resource at any instant. Otherwise, it returns *ConflictError.
+// The empty TimeInterval is not reserved, with ErrEmptyTimeInterval.
+func (b *Booker) Reserve(name string, ti *TimeInterval) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r := b.get(name)
+
+	if err := r.check(name, ti); err != nil {
+		return err
+	}
+
+	r.add(ti)
+
+	return nil
+}
+
+// Release removes a reservation equal to ti and reports whether it existed.
+func (b *Booker) Release(name string, ti *TimeInterval) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.get(name).remove(ti)
+}
+
+// Move replaces the reservation from with to atomically.
+// On conflict, the reservation from is kept.
+func (b *Booker) Move(name string, from, to *TimeInterval) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r := b.get(name)
+
+	if !r.remove(from) {
+		return ErrNotReserved
+	}
+
+	if err := r.check(name, to); err != nil {
+		r.add(from)
+		return err
+	}
+
+	r.add(to)
+
+	return nil
+}
+
+func (b *Booker) get(name string) *bookerResource {
+	if val, ok := b.resources[name]; ok {
+		return val
+	} else {
+		panic("invalid resource name: " + name)
+	}
+}
+
+func (r *bookerResource) add(ti *TimeInterval) {
+	r.reservations.Add(ti)
+	r.reservations.Sort()
+}
+
+func (r *bookerResource) remove(ti *TimeInterval) bool {
+	i := slices.IndexFunc(r.reservations.elements, ti.Equal)
+	if i < 0 {
+		return false
+	}
+
+	r.reservations.elements = slices.Delete(r.reservations.elements, i, i+1)
+
+	return true
+}
+
+func (r *bookerResource) check(name string, ti *TimeInterval) error {
+	if ti.IsEmpty() {
+		return ErrEmptyTimeInterval
+	}
+
+	full := saturated(ti, r.reservations.elements, r.capacity)
+	if len(full) == 0 {
+		return nil
+	}
+
+	conflicts := []*TimeInterval{}
+	for _, v := range r.reservations.elements {
+		if v.Intersects(ti) && slices.ContainsFunc(full, func(f *TimeInterval) bool {
+			return v.Intersects(f) || v.Equal(f)
+		}) {
+			conflicts = append(conflicts, v)
+		}
+	}
+
+	return &ConflictError{
+		Resource:  name,
+		Interval:  ti,
+		Conflicts: conflicts,
+	}
+}
+
+// saturated returns the parts of window where at least capacity elements
+// are overlapping each other.
+// A zero duration element or window overlaps only the elements strictly containing its instant,
+// as Intersects() does, and its instant is returned if it is at capacity with them.
+func saturated(window *TimeInterval, elements []*TimeInterval, capacity int) []*TimeInterval {
+	// containing returns the number of the elements strictly containing tp
+	containing := func(tp *TimePoint) int {
+		ret := 0
+		for _, v := range elements {
+			if v.start.Before(tp) && v.end.After(tp) {
+				ret++
+			}
+		}
+		return ret
+	}
+
+	if window.IsZeroDuration() {
+		if containing(window.start) >= capacity {
+			return []*TimeInterval{window}
+		}
+		return []*TimeInterval{}
+	}
+
+	type event struct {
+		tp    *TimePoint
+		delta int
+	}
+
+	events := []event{}
+	for _, v := range elements {
+		if v.IsZeroDuration() || !v.Intersects(window) {
+			continue
+		}
+
+		events = append(events,
+			event{tp: TimePointMax(v.start, window.start), delta: +1},
+			event{tp: TimePointMin(v.end, window.end), delta: -1},
+		)
+	}
+
+	// TimeInterval 끼리 바로 붙어 있는 경우는 겹치는 것이 아니므로
+	// 같은 시점에서는 끝나는 쪽을 먼저 처리
+	slices.SortFunc(events, func(a, b event) int {
+		if a.tp.Before(b.tp) {
+			return -1
+		}
+		if a.tp.After(b.tp) {
+			return 1
+		}
+		return a.delta - b.delta
+	})
+
+	ret := []*TimeInterval{}
+
+	depth := 0
+	var start *TimePoint
+	for _, e := range events {
+		depth += e.delta
+
+		if depth >= capacity && start == nil {
+			start = e.tp
+		}
+
+		if depth < capacity && start != nil {
+			if e.tp.After(start) {
+				ret = append(ret, NewTimeInterval(start, e.tp))
+			}
+			start = nil
+		}
+	}
+
+	for _, v := range elements {
+		if v.IsZeroDuration() && v.Intersects(window) && containing(v.start)+1 >= capacity {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,559 @@
+package timeinterval
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type EpochUnit int
+
+const (
+	EpochNone EpochUnit = iota // numeric timestamps are not allowed
+	EpochSeconds
+	EpochMilliseconds
+	EpochMicroseconds
+	EpochNanoseconds
+)
+
+func (u EpochUnit) duration() time.Duration {
+	switch u {
+	case EpochSeconds:
+		return time.Second
+	case EpochMilliseconds:
+		return time.Millisecond
+	case EpochMicroseconds:
+		return time.Microsecond
+	case EpochNanoseconds:
+		return time.Nanosecond
+	default:
+		panic(fmt.Sprint("invalid EpochUnit: ", int(u)))
+	}
+}
+
+// ColumnMapping
+//
+// Maps CSV columns or JSON fields to TimeInterval.
+// Start and one of End or Duration are required.
+type ColumnMapping struct {
+	Start    string // name of the column or the field of start
+	End      string // name of the column or the field of end
+	Duration string // name of the column or the field of duration, used if End is empty
+
+	// Layouts are tried in order for string timestamps.
+	// Default is []string{time.RFC3339Nano}.
+	Layouts []string
+	// Location of timestamps without zone. Default is time.UTC.
+	Location *time.Location
+	// EpochUnit of numeric timestamps
+	EpochUnit EpochUnit
+	// DurationUnit of numeric durations. Default is time.Second.
+	// Durations can also be strings parsed by time.ParseDuration().
+	// Writers write numeric durations only if it is set.
+	DurationUnit time.Duration
+
+	// CSV only. If true, there is no header and
+	// Start, End and Duration are zero-based column indexes, e.g. "0".
+	NoHeader bool
+}
+
+func (m *ColumnMapping) validate() {
+	if m.Start == "" || (m.End == "" && m.Duration == "") {
+		panic("invalid ColumnMapping: Start and End or Duration are required")
+	}
+}
+
+func (m *ColumnMapping) layouts() []string {
+	if len(m.Layouts) == 0 {
+		return []string{time.RFC3339Nano}
+	}
+	return m.Layouts
+}
+
+func (m *ColumnMapping) location() *time.Location {
+	if m.Location == nil {
+		return time.UTC
+	}
+	return m.Location
+}
+
+// decimalPattern is the numeric epoch timestamps and durations
+var decimalPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// ParseTimePoint parses a timestamp as the readers do
+func (m *ColumnMapping) ParseTimePoint(s string) (*TimePoint, error) {
+	s = strings.TrimSpace(s)
+
+	// big.Rat also takes fractions, hex and exponents, e.g. "3/2", which are not timestamps
+	if m.EpochUnit != EpochNone && decimalPattern.MatchString(s) {
+		if r, ok := new(big.Rat).SetString(s); ok {
+			ns := r.Mul(r, new(big.Rat).SetInt64(int64(m.EpochUnit.duration())))
+			if !ns.IsInt() {
+				return nil, fmt.Errorf("invalid epoch timestamp: %q", s)
+			}
+			// split into seconds and nanoseconds, as int64 nanoseconds cover only 1678 to 2262
+			sec, nsec := new(big.Int).DivMod(ns.Num(), big.NewInt(int64(time.Second)), new(big.Int))
+			if !sec.IsInt64() {
+				return nil, fmt.Errorf("invalid epoch timestamp: %q", s)
+			}
+			return FromUnix(sec.Int64(), nsec.Int64()), nil
+		}
+	}
+
+	for _, layout := range m.layouts() {
+		if t, err := time.ParseInLocation(layout, s, m.location()); err == nil {
+			return FromTime(t), nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid timestamp: %q", s)
+}
+
+func (m *ColumnMapping) parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	if !decimalPattern.MatchString(s) {
+		return time.ParseDuration(s)
+	}
+
+	unit := m.DurationUnit
+	if unit == 0 {
+		unit = time.Second
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	d := f * float64(unit)
+	// float64(math.MaxInt64) is 2^63, out of the range
+	if err != nil || d >= float64(math.MaxInt64) || d < float64(math.MinInt64) {
+		return time.Duration(0), fmt.Errorf("duration out of range: %q", s)
+	}
+
+	return time.Duration(d), nil
+}
+
+// FormatTimePoint formats tp as the writers do
+func (m *ColumnMapping) FormatTimePoint(tp *TimePoint) string {
+	if m.EpochUnit != EpochNone {
+		ns := big.NewInt(tp.t.Unix())
+		ns.Mul(ns, big.NewInt(int64(time.Second)))
+		ns.Add(ns, big.NewInt(int64(tp.t.Nanosecond())))
+		unit := m.EpochUnit.duration()
+		s := new(big.Rat).SetFrac(ns, big.NewInt(int64(unit))).FloatString(len(strconv.Itoa(int(unit))) - 1)
+		if strings.Contains(s, ".") {
+			s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		}
+		return s
+	}
+
+	return tp.t.In(m.location()).Format(m.layouts()[0])
+}
+
+func (m *ColumnMapping) formatDuration(d time.Duration) string {
+	if m.DurationUnit != 0 {
+		return strconv.FormatFloat(float64(d)/float64(m.DurationUnit), 'f', -1, 64)
+	}
+
+	return d.String()
+}
+
+// interval builds a TimeInterval from the raw values of Start and End or Duration
+func (m *ColumnMapping) interval(start, endOrDuration string) (*TimeInterval, error) {
+	s, err := m.ParseTimePoint(start)
+	if err != nil {
+		return nil, err
+	}
+
+	var e *TimePoint
+	if m.End != "" {
+		e, err = m.ParseTimePoint(endOrDuration)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		d, err := m.parseDuration(endOrDuration)
+		if err != nil {
+			return nil, err
+		}
+		e = FromTime(s.t.Add(d))
+	}
+
+	if e.Before(s) {
+		return nil, fmt.Errorf("end is before start: %s, %s", start, endOrDuration)
+	}
+
+	return NewTimeInterval(s, e), nil
+}
+
+func (m *ColumnMapping) second() string {
+	if m.End != "" {
+		return m.End
+	}
+	return m.Duration
+}
+
+// RowError reports a bad row. Readers can go on reading after it.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+type IntervalReader interface {
+	// Read returns the next TimeInterval, *RowError for a bad row, or io.EOF at the end
+	Read() (*TimeInterval, error)
+}
+
+// ErrEmptyTimeInterval is returned for the empty TimeInterval, e.g. by IntervalWriter as it has no columns
+// and by Booker as it reserves nothing
+var ErrEmptyTimeInterval = errors.New("timeinterval: empty TimeInterval")
+
+type IntervalWriter interface {
+	Write(ti *TimeInterval) error
+	Flush() error
+}
+
+// ReadIntoSet adds all TimeIntervals read from r to tis.
+// Bad rows are skipped and returned. Any other error stops reading.
+func ReadIntoSet(r IntervalReader, tis *TimeIntervalSet) ([]*RowError, error) {
+	ret := []*RowError{}
+
+	for {
+		ti, err := r.Read()
+		if err == io.EOF {
+			return ret, nil
+		}
+
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			ret = append(ret, rowErr)
+			continue
+		}
+		if err != nil {
+			return ret, err
+		}
+
+		tis.Add(ti)
+	}
+}
+
+// WriteSet writes all elements of tis to w and flushes w
+func WriteSet(w IntervalWriter, tis *TimeIntervalSet) error {
+	for _, ti := range tis.elements {
+		if err := w.Write(ti); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// CSVIntervalReader
+type CSVIntervalReader struct {
+	r *csv.Reader
+	m *ColumnMapping
+
+	first  int
+	second int
+	header bool  // true if the header is read or there is no header
+	err    error // error of the header, returned by every Read()
+}
+
+func NewCSVIntervalReader(r io.Reader, m *ColumnMapping) *CSVIntervalReader {
+	m.validate()
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	ret := &CSVIntervalReader{
+		r: cr,
+		m: m,
+	}
+
+	return ret
+}
+
+func (r *CSVIntervalReader) readHeader() error {
+	if r.m.NoHeader {
+		var err error
+		if r.first, err = strconv.Atoi(r.m.Start); err != nil || r.first < 0 {
+			return fmt.Errorf("invalid column index: %q", r.m.Start)
+		}
+		if r.second, err = strconv.Atoi(r.m.second()); err != nil || r.second < 0 {
+			return fmt.Errorf("invalid column index: %q", r.m.second())
+		}
+		return nil
+	}
+
+	record, err := r.r.Read()
+	if err != nil {
+		return err
+	}
+
+	r.first, r.second = -1, -1
+	for i, name := range record {
+		switch strings.TrimSpace(name) {
+		case r.m.Start:
+			r.first = i
+		case r.m.second():
+			r.second = i
+		}
+	}
+
+	if r.first < 0 {
+		return fmt.Errorf("no such column: %q", r.m.Start)
+	}
+	if r.second < 0 {
+		return fmt.Errorf("no such column: %q", r.m.second())
+	}
+
+	return nil
+}
+
+func (r *CSVIntervalReader) Read() (*TimeInterval, error) {
+	if !r.header {
+		r.header = true
+		r.err = r.readHeader()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	record, err := r.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &RowError{Line: parseErr.Line, Err: parseErr.Err}
+		}
+		return nil, err
+	}
+
+	line, _ := r.r.FieldPos(0)
+
+	if r.first >= len(record) || r.second >= len(record) {
+		return nil, &RowError{Line: line, Err: fmt.Errorf("too few columns: %d", len(record))}
+	}
+
+	ti, err := r.m.interval(record[r.first], record[r.second])
+	if err != nil {
+		return nil, &RowError{Line: line, Err: err}
+	}
+
+	return ti, nil
+}
+
+// CSVIntervalWriter
+type CSVIntervalWriter struct {
+	w *csv.Writer
+	m *ColumnMapping
+
+	header bool
+}
+
+func NewCSVIntervalWriter(w io.Writer, m *ColumnMapping) *CSVIntervalWriter {
+	m.validate()
+
+	ret := &CSVIntervalWriter{
+		w: csv.NewWriter(w),
+		m: m,
+
+		header: m.NoHeader,
+	}
+
+	return ret
+}
+
+func (w *CSVIntervalWriter) Write(ti *TimeInterval) error {
+	if ti.IsEmpty() {
+		return ErrEmptyTimeInterval
+	}
+
+	if !w.header {
+		w.header = true
+		if err := w.w.Write([]string{w.m.Start, w.m.second()}); err != nil {
+			return err
+		}
+	}
+
+	return w.w.Write([]string{w.m.FormatTimePoint(ti.start), w.m.formatSecond(ti)})
+}
+
+func (w *CSVIntervalWriter) Flush() error {
+	w.w.Flush()
+
+	return w.w.Error()
+}
+
+func (m *ColumnMapping) formatSecond(ti *TimeInterval) string {
+	if m.End != "" {
+		return m.FormatTimePoint(ti.end)
+	}
+	return m.formatDuration(ti.Duration())
+}
+
+// JSONLinesIntervalReader
+//
+// Fields can be nested with ".", e.g. "period.start".
+// Values can be strings or numbers.
+type JSONLinesIntervalReader struct {
+	s *bufio.Scanner
+	m *ColumnMapping
+
+	line int
+}
+
+func NewJSONLinesIntervalReader(r io.Reader, m *ColumnMapping) *JSONLinesIntervalReader {
+	m.validate()
+
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1024*1024)
+
+	ret := &JSONLinesIntervalReader{
+		s: s,
+		m: m,
+	}
+
+	return ret
+}
+
+func (r *JSONLinesIntervalReader) Read() (*TimeInterval, error) {
+	for r.s.Scan() {
+		r.line++
+
+		line := strings.TrimSpace(r.s.Text())
+		if line == "" {
+			continue
+		}
+
+		ti, err := r.parse(line)
+		if err != nil {
+			return nil, &RowError{Line: r.line, Err: err}
+		}
+
+		return ti, nil
+	}
+
+	if err := r.s.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+func (r *JSONLinesIntervalReader) parse(line string) (*TimeInterval, error) {
+	d := json.NewDecoder(strings.NewReader(line))
+	d.UseNumber()
+
+	var v map[string]any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	first, err := jsonField(v, r.m.Start)
+	if err != nil {
+		return nil, err
+	}
+	second, err := jsonField(v, r.m.second())
+	if err != nil {
+		return nil, err
+	}
+
+	return r.m.interval(first, second)
+}
+
+func jsonField(v map[string]any, name string) (string, error) {
+	var cur any = v
+
+	for _, key := range strings.Split(name, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("no such field: %q", name)
+		}
+		if cur, ok = obj[key]; !ok {
+			return "", fmt.Errorf("no such field: %q", name)
+		}
+	}
+
+	switch val := cur.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	default:
+		return "", fmt.Errorf("invalid value of field %q: %v", name, cur)
+	}
+}
+
+// JSONLinesIntervalWriter
+//
+// Writes an object per line with Start and End or Duration fields.
+// Epoch timestamps and numeric durations are written as numbers.
+// Nested field names are not supported.
+type JSONLinesIntervalWriter struct {
+	w *bufio.Writer
+	m *ColumnMapping
+}
+
+func NewJSONLinesIntervalWriter(w io.Writer, m *ColumnMapping) *JSONLinesIntervalWriter {
+	m.validate()
+
+	ret := &JSONLinesIntervalWriter{
+		w: bufio.NewWriter(w),
+		m: m,
+	}
+
+	return ret
+}
+
+func (w *JSONLinesIntervalWriter) Write(ti *TimeInterval) error {
+	if ti.IsEmpty() {
+		return ErrEmptyTimeInterval
+	}
+
+	value := func(s string, numeric bool) any {
+		if numeric {
+			return json.Number(s)
+		}
+		return s
+	}
+
+	v := map[string]any{
+		w.m.Start: value(w.m.FormatTimePoint(ti.start), w.m.EpochUnit != EpochNone),
+	}
+	if w.m.End != "" {
+		v[w.m.End] = value(w.m.FormatTimePoint(ti.end), w.m.EpochUnit != EpochNone)
+	} else {
+		v[w.m.Duration] = value(w.m.formatDuration(ti.Duration()), w.m.DurationUnit != 0)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+
+	return w.w.WriteByte('\n')
+}
+
+func (w *JSONLinesIntervalWriter) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,211 @@
+package timeinterval
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock
+//
+// Source of the current time and timers, replaceable by FakeClock in tests.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Now returns the current time of c
+func Now(c Clock) *TimePoint {
+	return FromTime(c.Now())
+}
+
+// Since returns the time elapsed since tp by c
+func Since(c Clock, tp *TimePoint) time.Duration {
+	return c.Now().Sub(tp.t)
+}
+
+// Until returns the time until tp by c
+func Until(c Clock, tp *TimePoint) time.Duration {
+	return tp.t.Sub(c.Now())
+}
+
+// ActiveNow reports whether ti has started and not ended yet by c.
+// Unlike Has(), the end is exclusive, as Scheduler calls OnEnd at the end.
+func ActiveNow(c Clock, ti *TimeInterval) bool {
+	if ti.IsEmpty() {
+		return false
+	}
+
+	now := Now(c)
+
+	return !now.Before(ti.start) && now.Before(ti.end)
+}
+
+// RelativeInterval returns the TimeInterval from now+from to now+to by c,
+// e.g. RelativeInterval(c, -24*time.Hour, 0) is the last 24 hours
+func RelativeInterval(c Clock, from, to time.Duration) *TimeInterval {
+	if to < from {
+		panic(fmt.Sprint("invalid range: ", from, ", ", to))
+	}
+
+	now := c.Now()
+
+	return NewTimeInterval(FromTime(now.Add(from)), FromTime(now.Add(to)))
+}
+
+// Timer is time.Timer of a Clock
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// RealClock is Clock of the time package
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (RealClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{t: time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (rt *realTimer) C() <-chan time.Time {
+	return rt.t.C
+}
+
+func (rt *realTimer) Stop() bool {
+	return rt.t.Stop()
+}
+
+// FakeClock
+//
+// Clock which moves only by Advance() and Set(), safe for concurrent use.
+type FakeClock struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	ret := &FakeClock{
+		now:    now,
+		timers: []*fakeTimer{},
+	}
+	ret.cond = sync.NewCond(&ret.mu)
+
+	return ret
+}
+
+func (fc *FakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	return fc.now
+}
+
+func (fc *FakeClock) NewTimer(d time.Duration) Timer {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	ft := &fakeTimer{
+		clock:    fc,
+		deadline: fc.now.Add(d),
+		c:        make(chan time.Time, 1),
+	}
+
+	if d <= 0 {
+		ft.c <- fc.now
+		return ft
+	}
+
+	fc.timers = append(fc.timers, ft)
+	fc.cond.Broadcast()
+
+	return ft
+}
+
+// Advance moves the clock forward by d and fires the timers due
+func (fc *FakeClock) Advance(d time.Duration) {
+	if d < 0 {
+		panic("negative duration")
+	}
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.set(fc.now.Add(d))
+}
+
+// Set moves the clock to t and fires the timers due. t must not be before Now().
+func (fc *FakeClock) Set(t time.Time) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if t.Before(fc.now) {
+		panic("the clock cannot go back")
+	}
+
+	fc.set(t)
+}
+
+func (fc *FakeClock) set(t time.Time) {
+	fc.now = t
+
+	// in the order of the deadlines
+	slices.SortStableFunc(fc.timers, func(a, b *fakeTimer) int {
+		return a.deadline.Compare(b.deadline)
+	})
+
+	i := 0
+	for ; i < len(fc.timers) && !fc.timers[i].deadline.After(t); i++ {
+		fc.timers[i].c <- t
+	}
+	fc.timers = slices.Delete(fc.timers, 0, i)
+
+	fc.cond.Broadcast()
+}
+
+// BlockUntil blocks until at least n timers are waiting
+func (fc *FakeClock) BlockUntil(n int) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	for len(fc.timers) < n {
+		fc.cond.Wait()
+	}
+}
+
+func (ft *fakeTimer) C() <-chan time.Time {
+	return ft.c
+}
+
+func (ft *fakeTimer) Stop() bool {
+	fc := ft.clock
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	i := slices.Index(fc.timers, ft)
+	if i < 0 {
+		return false
+	}
+
+	fc.timers = slices.Delete(fc.timers, i, i+1)
+	fc.cond.Broadcast()
+
+	return true
+}
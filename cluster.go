@@ -0,0 +1,74 @@
+package timeinterval
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Cluster is a group of TimeIntervals separated by gaps not longer than a tolerance
+type Cluster struct {
+	// Span covers all of Members, including the gaps between them
+	Span *TimeInterval
+
+	// Members are the original TimeIntervals, sorted by start, then by end
+	Members []*TimeInterval
+}
+
+// Clusters groups the elements of tis into Clusters, sorted by start.
+// Two elements are in the same Cluster if the gap between them is not longer than tolerance,
+// so Clusters(0) groups them as Cleanup(false) merges them.
+// Zero duration elements are kept, e.g. activity pings, each of which can be a Cluster alone,
+// but the empty elements are not.
+// tis is not modified.
+func (tis *TimeIntervalSet) Clusters(tolerance time.Duration) []*Cluster {
+	if tolerance < 0 {
+		panic(fmt.Sprint("negative tolerance: ", tolerance))
+	}
+
+	sorted := slices.DeleteFunc(slices.Clone(tis.elements), (*TimeInterval).IsEmpty)
+	slices.SortFunc(sorted, compareTimeInterval)
+
+	ret := []*Cluster{}
+
+	var start, end *TimePoint
+	first := 0
+
+	for i, v := range sorted {
+		if i > 0 && v.start.sub(end) <= tolerance {
+			end = TimePointMax(end, v.end)
+			continue
+		}
+
+		if i > 0 {
+			ret = append(ret, &Cluster{
+				Span:    NewTimeInterval(start, end),
+				Members: sorted[first:i:i],
+			})
+		}
+
+		start, end = v.start, v.end
+		first = i
+	}
+
+	if len(sorted) > 0 {
+		ret = append(ret, &Cluster{
+			Span:    NewTimeInterval(start, end),
+			Members: sorted[first:],
+		})
+	}
+
+	return ret
+}
+
+// Coalesce is Cleanup(false) also merging the elements separated by gaps
+// not longer than tolerance. The gaps merged are covered by the result.
+func (tis *TimeIntervalSet) Coalesce(tolerance time.Duration) {
+	clusters := tis.Clusters(tolerance)
+
+	tis.Clear()
+
+	for _, v := range clusters {
+		tis.Add(v.Span)
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iloy/timeinterval"
+)
+
+// defaultLayouts are the layouts of timestamps unless -layout is given
+var defaultLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+var epochUnits = map[string]timeinterval.EpochUnit{
+	"s":  timeinterval.EpochSeconds,
+	"ms": timeinterval.EpochMilliseconds,
+	"us": timeinterval.EpochMicroseconds,
+	"ns": timeinterval.EpochNanoseconds,
+}
+
+// ioFormat is how the intervals are read and written
+type ioFormat struct {
+	input  string // auto, iso, csv or jsonl
+	output string // iso, csv or jsonl
+
+	// m maps the columns of CSV and the fields of JSON lines,
+	// and parses and formats the timestamps of all the formats but the ISO 8601 output
+	m *timeinterval.ColumnMapping
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// isoDuration is an ISO 8601 duration, e.g. P1DT2H30M
+type isoDuration struct {
+	years, months, days int
+	d                   time.Duration
+}
+
+func parseISODuration(s string) (isoDuration, error) {
+	m := isoDurationPattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return isoDuration{}, fmt.Errorf("invalid duration: %q", s)
+	}
+
+	errRange := fmt.Errorf("duration out of range: %q", s)
+
+	// years, months, weeks, days, hours and minutes
+	n := [6]int{}
+	for i, v := range m[1:7] {
+		if v == "" {
+			continue
+		}
+		var err error
+		if n[i], err = strconv.Atoi(v); err != nil {
+			return isoDuration{}, errRange
+		}
+	}
+
+	if n[2] > (math.MaxInt-n[3])/7 || n[4] > math.MaxInt64/int(time.Hour) || n[5] > math.MaxInt64/int(time.Minute) {
+		return isoDuration{}, errRange
+	}
+
+	hours, minutes := time.Duration(n[4])*time.Hour, time.Duration(n[5])*time.Minute
+	if hours > math.MaxInt64-minutes {
+		return isoDuration{}, errRange
+	}
+
+	ret := isoDuration{
+		years:  n[0],
+		months: n[1],
+		days:   n[2]*7 + n[3],
+		d:      hours + minutes,
+	}
+
+	if m[7] != "" {
+		sec, err := strconv.ParseFloat(strings.Replace(m[7], ",", ".", 1), 64)
+		// float64(math.MaxInt64) is 2^63, out of the range
+		if err != nil || sec*float64(time.Second) >= float64(math.MaxInt64) {
+			return isoDuration{}, errRange
+		}
+		seconds := time.Duration(sec * float64(time.Second))
+		if ret.d > math.MaxInt64-seconds {
+			return isoDuration{}, errRange
+		}
+		ret.d += seconds
+	}
+
+	return ret, nil
+}
+
+func (d isoDuration) addTo(t time.Time, sign int) time.Time {
+	return t.AddDate(sign*d.years, sign*d.months, sign*d.days).Add(time.Duration(sign) * d.d)
+}
+
+func newTimeInterval(start, end time.Time) (*timeinterval.TimeInterval, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("end is before start: %s, %s", start.Format(time.RFC3339Nano), end.Format(time.RFC3339Nano))
+	}
+
+	return timeinterval.NewTimeInterval(timeinterval.FromTime(start), timeinterval.FromTime(end)), nil
+}
+
+// parseISOInterval parses start/end, start/duration or duration/end.
+// The timestamps are parsed by m.
+func parseISOInterval(s string, m *timeinterval.ColumnMapping) (*timeinterval.TimeInterval, error) {
+	first, second, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid interval: %q", s)
+	}
+
+	if strings.HasPrefix(first, "P") {
+		d, err := parseISODuration(first)
+		if err != nil {
+			return nil, err
+		}
+		end, err := m.ParseTimePoint(second)
+		if err != nil {
+			return nil, err
+		}
+		return newTimeInterval(d.addTo(end.ToTime(), -1), end.ToTime())
+	}
+
+	start, err := m.ParseTimePoint(first)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(second, "P") {
+		d, err := parseISODuration(second)
+		if err != nil {
+			return nil, err
+		}
+		return newTimeInterval(start.ToTime(), d.addTo(start.ToTime(), +1))
+	}
+
+	end, err := m.ParseTimePoint(second)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTimeInterval(start.ToTime(), end.ToTime())
+}
+
+// isoIntervalReader reads an ISO 8601 interval per line.
+// Empty lines and lines starting with '#' are skipped.
+type isoIntervalReader struct {
+	s *bufio.Scanner
+	m *timeinterval.ColumnMapping
+
+	line int
+}
+
+func newISOIntervalReader(r io.Reader, m *timeinterval.ColumnMapping) *isoIntervalReader {
+	ret := &isoIntervalReader{
+		s: bufio.NewScanner(r),
+		m: m,
+	}
+
+	return ret
+}
+
+func (r *isoIntervalReader) Read() (*timeinterval.TimeInterval, error) {
+	for r.s.Scan() {
+		r.line++
+
+		line := strings.TrimSpace(r.s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		ti, err := parseISOInterval(line, r.m)
+		if err != nil {
+			return nil, &timeinterval.RowError{Line: r.line, Err: err}
+		}
+
+		return ti, nil
+	}
+
+	if err := r.s.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// detectFormat detects the format of r from its first line
+// other than the empty lines and the comments.
+// The lines read are kept in head, to be read again before the rest of r.
+func detectFormat(r *bufio.Reader, head *bytes.Buffer) (string, error) {
+	for {
+		s, err := r.ReadString('\n')
+		head.WriteString(s)
+
+		line := strings.TrimSpace(s)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "{"):
+			return "jsonl", nil
+		case strings.Contains(line, "/"):
+			return "iso", nil
+		default:
+			return "csv", nil
+		}
+
+		if err == io.EOF {
+			return "iso", nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// readIntervals reads the intervals in f.input, detected from the content for "auto".
+// The first bad line is returned as the error.
+func readIntervals(r io.Reader, name string, f *ioFormat) (*timeinterval.TimeIntervalSet, error) {
+	format := f.input
+
+	if format == "auto" {
+		br := bufio.NewReader(r)
+		head := &bytes.Buffer{}
+
+		var err error
+		if format, err = detectFormat(br, head); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		r = io.MultiReader(head, br)
+	}
+
+	var ir timeinterval.IntervalReader
+	switch format {
+	case "iso":
+		ir = newISOIntervalReader(r, f.m)
+	case "csv":
+		ir = timeinterval.NewCSVIntervalReader(r, f.m)
+	case "jsonl":
+		ir = timeinterval.NewJSONLinesIntervalReader(r, f.m)
+	default:
+		return nil, fmt.Errorf("%w: unknown input format: %q", errUsage, format)
+	}
+
+	ret := timeinterval.NewTimeIntervalSet()
+
+	rowErrs, err := timeinterval.ReadIntoSet(ir, ret)
+	if len(rowErrs) > 0 {
+		return nil, fmt.Errorf("%s:%d: %w", name, rowErrs[0].Line, rowErrs[0].Err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return ret, nil
+}
+
+func formatTime(tp *timeinterval.TimePoint) string {
+	return tp.ToTime().Format(time.RFC3339Nano)
+}
+
+// isoIntervalWriter writes an ISO 8601 interval per line, in RFC 3339 regardless of the layouts
+type isoIntervalWriter struct {
+	w *bufio.Writer
+}
+
+func (w *isoIntervalWriter) Write(ti *timeinterval.TimeInterval) error {
+	if ti.IsEmpty() {
+		return timeinterval.ErrEmptyTimeInterval
+	}
+
+	_, err := fmt.Fprintf(w.w, "%s/%s\n", formatTime(ti.Start()), formatTime(ti.End()))
+
+	return err
+}
+
+func (w *isoIntervalWriter) Flush() error {
+	return w.w.Flush()
+}
+
+func newIntervalWriter(w io.Writer, f *ioFormat) (timeinterval.IntervalWriter, error) {
+	switch f.output {
+	case "iso":
+		return &isoIntervalWriter{w: bufio.NewWriter(w)}, nil
+	case "csv":
+		return timeinterval.NewCSVIntervalWriter(w, f.m), nil
+	case "jsonl":
+		return timeinterval.NewJSONLinesIntervalWriter(w, f.m), nil
+	default:
+		return nil, fmt.Errorf("unknown output format: %q", f.output)
+	}
+}
+
+func writeSet(w io.Writer, f *ioFormat, tis *timeinterval.TimeIntervalSet) error {
+	iw, err := newIntervalWriter(w, f)
+	if err != nil {
+		return err
+	}
+
+	return timeinterval.WriteSet(iw, tis)
+}
+
+// writeSummary writes the buckets of summary followed by their covered durations.
+// The columns of CSV and the fields of JSON lines are the start and the end of f.m,
+// "end" if f.m has a duration instead, and the covered duration.
+func writeSummary(w io.Writer, f *ioFormat, buckets []*bucketSummary) error {
+	m := f.m
+
+	end := m.End
+	if end == "" {
+		end = "end"
+	}
+
+	timestamp := func(tp *timeinterval.TimePoint) any {
+		if m.EpochUnit != timeinterval.EpochNone {
+			return json.Number(m.FormatTimePoint(tp))
+		}
+		return m.FormatTimePoint(tp)
+	}
+
+	switch f.output {
+	case "iso":
+		bw := bufio.NewWriter(w)
+		for _, b := range buckets {
+			if _, err := fmt.Fprintf(bw, "%s/%s\t%s\n", formatTime(b.ti.Start()), formatTime(b.ti.End()), b.covered); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+
+	case "csv":
+		cw := csv.NewWriter(w)
+		if !m.NoHeader {
+			if err := cw.Write([]string{m.Start, end, "seconds"}); err != nil {
+				return err
+			}
+		}
+		for _, b := range buckets {
+			seconds := strconv.FormatFloat(b.covered.Seconds(), 'f', -1, 64)
+			if err := cw.Write([]string{m.FormatTimePoint(b.ti.Start()), m.FormatTimePoint(b.ti.End()), seconds}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case "jsonl":
+		bw := bufio.NewWriter(w)
+		for _, b := range buckets {
+			v := map[string]any{
+				m.Start:    timestamp(b.ti.Start()),
+				end:        timestamp(b.ti.End()),
+				"duration": b.covered.String(),
+				"seconds":  b.covered.Seconds(),
+			}
+			line, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(bw, "%s\n", line); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+
+	default:
+		return fmt.Errorf("unknown output format: %q", f.output)
+	}
+}
@@ -0,0 +1,261 @@
+// Command timeinterval does interval arithmetic on files.
+//
+// Usage:
+//
+//	timeinterval [flags] <command> [file...]
+//
+// Each input has an interval per line in one of the formats below.
+// The format is detected from the first line of each input unless -i is given.
+// "-" or no file means stdin.
+//
+//	2024-02-11T19:00:00Z/2024-02-11T20:00:00Z          ISO 8601 start/end
+//	2024-02-11T19:00:00Z/PT1H                          ISO 8601 start/duration
+//	PT1H/2024-02-11T20:00:00Z                          ISO 8601 duration/end
+//	2024-02-11T19:00:00Z,2024-02-11T20:00:00Z          CSV, after the header "start,end"
+//	{"start":"2024-02-11T19:00:00Z","end":"2024-02-11T20:00:00Z"}  JSON lines
+//
+// Empty lines and lines starting with '#' are skipped in ISO 8601.
+// The columns of CSV and the fields of JSON lines are set by -start and -end or -duration,
+// and the timestamps of all the formats by -layout and -epoch, for the output too,
+// except that the ISO 8601 output is always in RFC 3339.
+//
+// Commands:
+//
+//	union     [file...]    union of all inputs
+//	cleanup   [file...]    same as union (alias: merge)
+//	intersect file1 file2  parts covered by both inputs
+//	subtract  file1 file2  parts covered by file1 but not by file2
+//	gaps      [file...]    gaps between intervals of the union
+//	duration  [file...]    total duration of the union
+//	summary   [file...]    covered duration of the union per bucket (see -bucket)
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/iloy/timeinterval"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("timeinterval", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: timeinterval [flags] <union|cleanup|merge|intersect|subtract|gaps|duration|summary> [file...]")
+		flags.PrintDefaults()
+	}
+
+	input := flags.String("i", "auto", "input format: auto, iso, csv or jsonl")
+	output := flags.String("o", "iso", "output format: iso, csv or jsonl")
+	start := flags.String("start", "", `column or field of start (default "start", or "0" with -no-header)`)
+	end := flags.String("end", "", `column or field of end (default "end", or "1" with -no-header)`)
+	duration := flags.String("duration", "", "column or field of duration in seconds or as 1h30m, used instead of -end")
+	noHeader := flags.Bool("no-header", false, "CSV without header, where the columns are zero-based indexes")
+	epochUnit := timeinterval.EpochNone
+	flags.Func("epoch", "unit of numeric timestamps: s, ms, us or ns", func(s string) error {
+		u, ok := epochUnits[s]
+		if !ok {
+			return fmt.Errorf("unknown epoch unit: %q", s)
+		}
+		epochUnit = u
+		return nil
+	})
+	layouts := []string{}
+	flags.Func("layout", "layout of timestamps as time.Parse(), repeatable (default RFC 3339 and the same without zone in UTC)", func(s string) error {
+		layouts = append(layouts, s)
+		return nil
+	})
+	bucket := flags.Duration("bucket", time.Hour, "bucket size of summary, which divides 24h or is a multiple of 24h.\n"+
+		"Buckets are aligned to UTC midnight, and those of multiples of 7 days to Monday")
+	keepZero := flags.Bool("keep-zero", false, "keep zero duration intervals in union, cleanup and merge")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if len(layouts) == 0 {
+		layouts = defaultLayouts
+	}
+
+	f := &ioFormat{
+		input:  *input,
+		output: *output,
+		m: &timeinterval.ColumnMapping{
+			Start:     *start,
+			End:       *end,
+			Duration:  *duration,
+			Layouts:   layouts,
+			EpochUnit: epochUnit,
+			NoHeader:  *noHeader,
+		},
+	}
+	if f.m.Start == "" {
+		f.m.Start = "start"
+		if *noHeader {
+			f.m.Start = "0"
+		}
+	}
+	if f.m.Duration != "" {
+		f.m.End = ""
+	} else if f.m.End == "" {
+		f.m.End = "end"
+		if *noHeader {
+			f.m.End = "1"
+		}
+	}
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	cmd, files := flags.Arg(0), flags.Args()[1:]
+
+	err := execute(cmd, files, stdin, stdout, f, *bucket, *keepZero)
+	if err != nil {
+		fmt.Fprintln(stderr, "timeinterval:", err)
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		return 1
+	}
+
+	return 0
+}
+
+var errUsage = errors.New("invalid usage")
+
+const day = 24 * time.Hour
+
+func execute(cmd string, files []string, stdin io.Reader, stdout io.Writer, f *ioFormat, bucket time.Duration, keepZero bool) error {
+	switch cmd {
+	case "union", "cleanup", "merge":
+		tis, err := readAll(files, stdin, f)
+		if err != nil {
+			return err
+		}
+		tis.Cleanup(!keepZero)
+		return writeSet(stdout, f, tis)
+
+	case "intersect", "subtract":
+		if len(files) != 2 {
+			return fmt.Errorf("%w: %s needs exactly two files", errUsage, cmd)
+		}
+		a, err := readAll(files[:1], stdin, f)
+		if err != nil {
+			return err
+		}
+		b, err := readAll(files[1:], stdin, f)
+		if err != nil {
+			return err
+		}
+		if cmd == "intersect" {
+			return writeSet(stdout, f, a.Intersect(b))
+		}
+		return writeSet(stdout, f, a.Subtract(b))
+
+	case "gaps":
+		tis, err := readAll(files, stdin, f)
+		if err != nil {
+			return err
+		}
+		return writeSet(stdout, f, tis.Gaps())
+
+	case "duration":
+		tis, err := readAll(files, stdin, f)
+		if err != nil {
+			return err
+		}
+		tis.Cleanup(true)
+		_, err = fmt.Fprintln(stdout, tis.WideDuration())
+		return err
+
+	case "summary":
+		if bucket <= 0 || day%bucket != 0 && bucket%day != 0 {
+			return fmt.Errorf("%w: bucket must divide 24h or be a multiple of 24h: %v", errUsage, bucket)
+		}
+		tis, err := readAll(files, stdin, f)
+		if err != nil {
+			return err
+		}
+		tis.Cleanup(true)
+		return writeSummary(stdout, f, summarize(tis, bucket))
+
+	default:
+		return fmt.Errorf("%w: unknown command: %q", errUsage, cmd)
+	}
+}
+
+func readAll(files []string, stdin io.Reader, f *ioFormat) (*timeinterval.TimeIntervalSet, error) {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	ret := timeinterval.NewTimeIntervalSet()
+
+	for _, name := range files {
+		var tis *timeinterval.TimeIntervalSet
+		var err error
+
+		if name == "-" {
+			tis, err = readIntervals(stdin, "<stdin>", f)
+		} else {
+			var file *os.File
+			file, err = os.Open(name)
+			if err != nil {
+				return nil, err
+			}
+			tis, err = readIntervals(file, name, f)
+			file.Close()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Merge(tis)
+	}
+
+	return ret, nil
+}
+
+type bucketSummary struct {
+	ti      *timeinterval.TimeInterval
+	covered time.Duration
+}
+
+// summarize returns the covered duration per bucket, for the buckets overlapping tis.
+// tis must be cleaned up, and bucket must divide 24h or be a multiple of 24h.
+// Truncate() aligns the buckets to the zero time, 0001-01-01T00:00:00Z,
+// which is a Monday midnight, so they are aligned to UTC midnight and weeks to Monday.
+func summarize(tis *timeinterval.TimeIntervalSet, bucket time.Duration) []*bucketSummary {
+	ret := []*bucketSummary{}
+
+	for _, ti := range tis.Elements() {
+		start, end := ti.Start().ToTime(), ti.End().ToTime()
+
+		for b := start.Truncate(bucket); b.Before(end); b = b.Add(bucket) {
+			bucketTi := timeinterval.NewTimeInterval(timeinterval.FromTime(b), timeinterval.FromTime(b.Add(bucket)))
+
+			if len(ret) == 0 || !ret[len(ret)-1].ti.Equal(bucketTi) {
+				ret = append(ret, &bucketSummary{ti: bucketTi})
+			}
+
+			overlap := timeinterval.NewTimeInterval(
+				timeinterval.TimePointMax(ti.Start(), bucketTi.Start()),
+				timeinterval.TimePointMin(ti.End(), bucketTi.End()),
+			)
+			ret[len(ret)-1].covered += overlap.Duration()
+		}
+	}
+
+	return ret
+}
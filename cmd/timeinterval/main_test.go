@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+	}{
+		{name: "union", args: []string{"union", "testdata/a.txt", "testdata/c.jsonl"}},
+		{name: "union_csv", args: []string{"-o", "csv", "union", "testdata/a.txt"}},
+		{name: "union_jsonl", args: []string{"-o", "jsonl", "union", "testdata/b.csv"}},
+		{name: "cleanup_keep_zero", args: []string{"-keep-zero", "cleanup", "testdata/a.txt"}},
+		{name: "intersect", args: []string{"intersect", "testdata/a.txt", "testdata/b.csv"}},
+		{name: "subtract", args: []string{"subtract", "testdata/a.txt", "testdata/b.csv"}},
+		{name: "gaps", args: []string{"gaps", "testdata/a.txt", "testdata/b.csv", "testdata/c.jsonl"}},
+		{name: "duration", args: []string{"duration", "testdata/a.txt"}},
+		{name: "summary", args: []string{"summary", "testdata/a.txt"}},
+		{name: "summary_jsonl", args: []string{"-o", "jsonl", "-bucket", "30m", "summary", "testdata/b.csv"}},
+		{name: "summary_day_csv", args: []string{"-o", "csv", "-bucket", "24h", "summary", "testdata/a.txt", "testdata/c.jsonl"}},
+		{name: "summary_week", args: []string{"-bucket", "168h", "summary", "testdata/a.txt"}},
+		{name: "epoch_no_header", args: []string{"-no-header", "-epoch", "s", "-duration", "1", "-o", "csv", "union", "testdata/d.csv"}},
+		{name: "fields_layout", args: []string{"-start", "period.begin", "-end", "period.finish", "-layout", "02/01/2006 15:04", "union", "testdata/e.jsonl"}},
+		{name: "input_format", args: []string{"-i", "iso", "-o", "jsonl", "-epoch", "ms", "union", "-"}, stdin: "1707678000000/PT1H\n"},
+		{name: "stdin", args: []string{"union", "-", "testdata/c.jsonl"}, stdin: "2024-02-11T20:05:00Z/PT10M\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, code, 0, stderr.String())
+
+			golden := filepath.Join("testdata", "golden", tt.name+".golden")
+			if *update {
+				assert.Nil(t, os.WriteFile(golden, stdout.Bytes(), 0o644))
+			}
+
+			want, err := os.ReadFile(golden)
+			assert.Nil(t, err)
+			assert.Equal(t, string(want), stdout.String())
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		args   []string
+		code   int
+		stderr string
+	}{
+		{args: []string{}, code: 2, stderr: "usage:"},
+		{args: []string{"unknown"}, code: 2, stderr: "unknown command"},
+		{args: []string{"intersect", "testdata/a.txt"}, code: 2, stderr: "needs exactly two files"},
+		{args: []string{"union", "testdata/bad.txt"}, code: 1, stderr: "testdata/bad.txt:2: end is before start"},
+		{args: []string{"union", "testdata/missing.txt"}, code: 1, stderr: "no such file"},
+		{args: []string{"-o", "xml", "union", "testdata/a.txt"}, code: 1, stderr: "unknown output format"},
+		{args: []string{"-start", "begin", "union", "testdata/b.csv"}, code: 1, stderr: "testdata/b.csv: no such column"},
+		{args: []string{"-i", "xml", "union", "testdata/a.txt"}, code: 2, stderr: "unknown input format"},
+		{args: []string{"-epoch", "h", "union", "testdata/a.txt"}, code: 2, stderr: "unknown epoch unit"},
+		{args: []string{"-bucket", "7h", "summary", "testdata/a.txt"}, code: 2, stderr: "bucket must divide 24h"},
+		{args: []string{"-bucket", "36h", "summary", "testdata/a.txt"}, code: 2, stderr: "bucket must divide 24h"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+
+		code := run(tt.args, strings.NewReader(""), &stdout, &stderr)
+		assert.Equal(t, code, tt.code, tt.args)
+		assert.Contains(t, stderr.String(), tt.stderr)
+	}
+}
+
+func TestParseISOInterval(t *testing.T) {
+	m := &timeinterval.ColumnMapping{Start: "start", End: "end", Layouts: defaultLayouts}
+
+	ti, err := parseISOInterval("2024-02-29T00:00:00Z/P1Y2M3W4DT5H6M7.5S", m)
+	assert.Nil(t, err)
+	assert.Equal(t, formatTime(ti.End()), "2025-05-24T05:06:07.5Z")
+
+	_, err = parseISOInterval("2024-02-29T00:00:00Z/P", m)
+	assert.NotNil(t, err)
+	_, err = parseISOInterval("2024-02-29T00:00:00Z/PT", m)
+	assert.NotNil(t, err)
+	_, err = parseISOInterval("2024-02-29T00:00:00Z", m)
+	assert.NotNil(t, err)
+
+	// out of range, not wrapped nor 0
+	for _, v := range []string{
+		"PT99999999999999999999H",
+		"PT2562048H",
+		"PT153722868M",
+		"PT2562047H60M",
+		"PT9223372037S",
+		"PT2562047H47M17S",
+		"P99999999999999999999D",
+		"P1317624576693539402W",
+	} {
+		_, err = parseISODuration(v)
+		assert.ErrorContains(t, err, "duration out of range", v)
+	}
+
+	d, err := parseISODuration("PT2562047H47M16S")
+	assert.Nil(t, err)
+	assert.Equal(t, d.d, time.Duration(math.MaxInt64/int64(time.Second)*int64(time.Second)))
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		input  string
+		format string
+	}{
+		{input: "# comment\n\n{\"start\":\"2024-02-11\",\"end\":\"2024-02-12\"}\n", format: "jsonl"},
+		{input: "2024-02-11T19:00:00Z/PT1H", format: "iso"},
+		{input: "start,end\n", format: "csv"},
+		{input: "# comment only\n", format: "iso"},
+		{input: "", format: "iso"},
+	}
+
+	for _, tt := range tests {
+		head := &bytes.Buffer{}
+		format, err := detectFormat(bufio.NewReader(strings.NewReader(tt.input)), head)
+		assert.Nil(t, err)
+		assert.Equal(t, format, tt.format, tt.input)
+	}
+
+	// only the head is read, and it is read again with the rest
+	input := "# comment\n" + strings.Repeat("2024-02-11T19:00:00Z/PT1H\n", 100000)
+	r := strings.NewReader(input)
+	br := bufio.NewReader(r)
+	head := &bytes.Buffer{}
+
+	format, err := detectFormat(br, head)
+	assert.Nil(t, err)
+	assert.Equal(t, format, "iso")
+	assert.Greater(t, r.Len(), len(input)/2)
+
+	all, err := io.ReadAll(io.MultiReader(head, br))
+	assert.Nil(t, err)
+	assert.Equal(t, string(all), input)
+}
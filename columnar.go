@@ -0,0 +1,291 @@
+package timeinterval
+
+import (
+	"sort"
+	"time"
+)
+
+// ColumnarTimeIntervalSet
+//
+// Counterpart of TimeIntervalSet for very large sets, keeping the starts and the ends
+// of the elements in two int64 slices of Unix nanoseconds, as CompactTimeInterval does.
+// It takes 16 bytes per element without any pointer for the GC to scan,
+// while TimeIntervalSet takes more than 200 bytes in three objects per element.
+// Like TimeIntervalSet, it is mutable. It has the set operations and the durations of TimeIntervalSet,
+// and TimeIntervalSet() converts it for the others, e.g. the transforms, Clusters() and Stats().
+type ColumnarTimeIntervalSet struct {
+	starts []int64
+	ends   []int64
+}
+
+func NewColumnarTimeIntervalSet() *ColumnarTimeIntervalSet {
+	ret := &ColumnarTimeIntervalSet{
+		starts: []int64{},
+		ends:   []int64{},
+	}
+
+	return ret
+}
+
+// Columnar returns the elements of tis as ColumnarTimeIntervalSet, without the empty elements.
+// It panics as CompactOf() does.
+func (tis *TimeIntervalSet) Columnar() *ColumnarTimeIntervalSet {
+	ret := &ColumnarTimeIntervalSet{
+		starts: make([]int64, 0, len(tis.elements)),
+		ends:   make([]int64, 0, len(tis.elements)),
+	}
+
+	for _, v := range tis.elements {
+		if v.IsEmpty() {
+			continue
+		}
+		ret.Add(CompactOf(v))
+	}
+
+	return ret
+}
+
+// TimeIntervalSet is the inverse of TimeIntervalSet.Columnar()
+func (ctis *ColumnarTimeIntervalSet) TimeIntervalSet() *TimeIntervalSet {
+	ret := NewTimeIntervalSet()
+
+	for i := range ctis.starts {
+		ret.Add(ctis.At(i).TimeInterval())
+	}
+
+	return ret
+}
+
+func (ctis *ColumnarTimeIntervalSet) Len() int {
+	return len(ctis.starts)
+}
+
+// At returns the i-th element
+func (ctis *ColumnarTimeIntervalSet) At(i int) CompactTimeInterval {
+	return CompactTimeInterval{Start: ctis.starts[i], End: ctis.ends[i]}
+}
+
+// Starts returns the starts of the elements, which must not be modified
+func (ctis *ColumnarTimeIntervalSet) Starts() []int64 {
+	return ctis.starts
+}
+
+// Ends returns the ends of the elements, which must not be modified
+func (ctis *ColumnarTimeIntervalSet) Ends() []int64 {
+	return ctis.ends
+}
+
+// Duration saturates as TimeIntervalSet.Duration() does
+func (ctis *ColumnarTimeIntervalSet) Duration() time.Duration {
+	ret := time.Duration(0)
+
+	for i := range ctis.starts {
+		ret = addSaturated(ret, ctis.At(i).Duration())
+	}
+
+	return ret
+}
+
+// WideDuration is TimeIntervalSet.WideDuration()
+func (ctis *ColumnarTimeIntervalSet) WideDuration() WideDuration {
+	ret := WideDuration{}
+
+	for i := range ctis.starts {
+		// apart, as the difference of the nanoseconds can overflow
+		start, end := ctis.starts[i], ctis.ends[i]
+		ret = ret.Add(NewWideDuration(end/int64(time.Second)-start/int64(time.Second), end%int64(time.Second)-start%int64(time.Second)))
+	}
+
+	return ret
+}
+
+// DurationChecked is TimeIntervalSet.DurationChecked()
+func (ctis *ColumnarTimeIntervalSet) DurationChecked() (time.Duration, error) {
+	if d, ok := ctis.WideDuration().Duration(); ok {
+		return d, nil
+	}
+
+	return time.Duration(0), ErrDurationOverflow
+}
+
+// Span is TimeIntervalSet.Span(). ok is false if ctis has no element,
+// as CompactTimeInterval cannot be the empty TimeInterval.
+func (ctis *ColumnarTimeIntervalSet) Span() (span CompactTimeInterval, ok bool) {
+	if len(ctis.starts) == 0 {
+		return CompactTimeInterval{}, false
+	}
+
+	ret := CompactTimeInterval{Start: ctis.starts[0], End: ctis.ends[0]}
+
+	for i := range ctis.starts {
+		ret.Start = min(ret.Start, ctis.starts[i])
+		ret.End = max(ret.End, ctis.ends[i])
+	}
+
+	return ret, true
+}
+
+func (ctis *ColumnarTimeIntervalSet) Copy() *ColumnarTimeIntervalSet {
+	ret := &ColumnarTimeIntervalSet{
+		starts: append([]int64{}, ctis.starts...),
+		ends:   append([]int64{}, ctis.ends...),
+	}
+
+	return ret
+}
+
+func (ctis *ColumnarTimeIntervalSet) Clear() {
+	ctis.starts = []int64{}
+	ctis.ends = []int64{}
+}
+
+func (ctis *ColumnarTimeIntervalSet) Add(c ...CompactTimeInterval) {
+	for _, v := range c {
+		ctis.starts = append(ctis.starts, v.Start)
+		ctis.ends = append(ctis.ends, v.End)
+	}
+}
+
+func (ctis *ColumnarTimeIntervalSet) Merge(ctis2 ...*ColumnarTimeIntervalSet) {
+	for _, v := range ctis2 {
+		ctis.starts = append(ctis.starts, v.starts...)
+		ctis.ends = append(ctis.ends, v.ends...)
+	}
+}
+
+// Sort sorts the elements as TimeIntervalSet.Sort() does
+func (ctis *ColumnarTimeIntervalSet) Sort() {
+	sort.Sort(columnarSorter{ctis})
+}
+
+type columnarSorter struct {
+	ctis *ColumnarTimeIntervalSet
+}
+
+func (s columnarSorter) Len() int {
+	return len(s.ctis.starts)
+}
+
+func (s columnarSorter) Less(i, j int) bool {
+	return s.ctis.At(i).Compare(s.ctis.At(j)) < 0
+}
+
+func (s columnarSorter) Swap(i, j int) {
+	starts, ends := s.ctis.starts, s.ctis.ends
+
+	starts[i], starts[j] = starts[j], starts[i]
+	ends[i], ends[j] = ends[j], ends[i]
+}
+
+// Cleanup cleans up the elements as TimeIntervalSet.Cleanup() does,
+// in O(n log n) time as CleanupCompact() does
+func (ctis *ColumnarTimeIntervalSet) Cleanup(removeZeroDuration bool) {
+	ctis.Sort()
+
+	starts, ends := ctis.starts, ctis.ends
+
+	n := 0
+	for i := range starts {
+		if removeZeroDuration && starts[i] == ends[i] {
+			continue
+		}
+
+		if n > 0 && starts[i] <= ends[n-1] {
+			ends[n-1] = max(ends[n-1], ends[i])
+			continue
+		}
+
+		starts[n], ends[n] = starts[i], ends[i]
+		n++
+	}
+
+	ctis.starts, ctis.ends = starts[:n], ends[:n]
+}
+
+// cleanedUp returns a copy of ctis cleaned up with removeZeroDuration
+func (ctis *ColumnarTimeIntervalSet) cleanedUp() *ColumnarTimeIntervalSet {
+	ret := ctis.Copy()
+
+	ret.Cleanup(true)
+
+	return ret
+}
+
+// Union is TimeIntervalSet.Union()
+func (ctis *ColumnarTimeIntervalSet) Union(ctis2 ...*ColumnarTimeIntervalSet) *ColumnarTimeIntervalSet {
+	ret := ctis.Copy()
+
+	ret.Merge(ctis2...)
+	ret.Cleanup(true)
+
+	return ret
+}
+
+// Intersect is TimeIntervalSet.Intersect()
+func (ctis *ColumnarTimeIntervalSet) Intersect(ctis2 *ColumnarTimeIntervalSet) *ColumnarTimeIntervalSet {
+	a := ctis.cleanedUp()
+	b := ctis2.cleanedUp()
+
+	ret := NewColumnarTimeIntervalSet()
+
+	for i, j := 0, 0; i < a.Len() && j < b.Len(); {
+		if a.starts[i] < b.ends[j] && a.ends[i] > b.starts[j] {
+			ret.Add(CompactTimeInterval{
+				Start: max(a.starts[i], b.starts[j]),
+				End:   min(a.ends[i], b.ends[j]),
+			})
+		}
+
+		if a.ends[i] < b.ends[j] {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return ret
+}
+
+// Subtract is TimeIntervalSet.Subtract()
+func (ctis *ColumnarTimeIntervalSet) Subtract(ctis2 *ColumnarTimeIntervalSet) *ColumnarTimeIntervalSet {
+	a := ctis.cleanedUp()
+	b := ctis2.cleanedUp()
+
+	ret := NewColumnarTimeIntervalSet()
+
+	j := 0
+	for i := range a.starts {
+		start := a.starts[i]
+
+		for j < b.Len() && b.ends[j] <= start {
+			j++
+		}
+
+		// b[k] may also cover the next element of a, so j is not advanced here
+		for k := j; k < b.Len() && b.starts[k] < a.ends[i]; k++ {
+			if b.starts[k] > start {
+				ret.Add(CompactTimeInterval{Start: start, End: b.starts[k]})
+			}
+			start = max(start, b.ends[k])
+		}
+
+		if a.ends[i] > start {
+			ret.Add(CompactTimeInterval{Start: start, End: a.ends[i]})
+		}
+	}
+
+	return ret
+}
+
+// Gaps is TimeIntervalSet.Gaps()
+func (ctis *ColumnarTimeIntervalSet) Gaps() *ColumnarTimeIntervalSet {
+	a := ctis.cleanedUp()
+
+	ret := NewColumnarTimeIntervalSet()
+
+	for i := 1; i < a.Len(); i++ {
+		ret.Add(CompactTimeInterval{Start: a.ends[i-1], End: a.starts[i]})
+	}
+
+	return ret
+}
@@ -0,0 +1,135 @@
+package timeinterval
+
+import (
+	"sync"
+	"time"
+)
+
+// ConcurrentTimeIntervalSet
+//
+// TimeIntervalSet guarded by sync.RWMutex, safe for concurrent use.
+// Every method returning a TimeIntervalSet returns a copy (snapshot).
+type ConcurrentTimeIntervalSet struct {
+	mu sync.RWMutex
+
+	tis *TimeIntervalSet
+}
+
+func NewConcurrentTimeIntervalSet() *ConcurrentTimeIntervalSet {
+	ret := &ConcurrentTimeIntervalSet{
+		tis: NewTimeIntervalSet(),
+	}
+
+	return ret
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Elements() []*TimeInterval {
+	ctis.mu.RLock()
+	defer ctis.mu.RUnlock()
+
+	return ctis.tis.Copy().elements
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Len() int {
+	ctis.mu.RLock()
+	defer ctis.mu.RUnlock()
+
+	return len(ctis.tis.elements)
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Duration() time.Duration {
+	ctis.mu.RLock()
+	defer ctis.mu.RUnlock()
+
+	return ctis.tis.Duration()
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Snapshot() *TimeIntervalSet {
+	ctis.mu.RLock()
+	defer ctis.mu.RUnlock()
+
+	return ctis.tis.Copy()
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Clear() {
+	ctis.mu.Lock()
+	defer ctis.mu.Unlock()
+
+	ctis.tis.Clear()
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Add(ti ...*TimeInterval) {
+	ctis.mu.Lock()
+	defer ctis.mu.Unlock()
+
+	ctis.tis.Add(ti...)
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Merge(tis2 ...*TimeIntervalSet) {
+	ctis.mu.Lock()
+	defer ctis.mu.Unlock()
+
+	ctis.tis.Merge(tis2...)
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Cleanup(removeZeroDuration bool) {
+	ctis.mu.Lock()
+	defer ctis.mu.Unlock()
+
+	ctis.tis.Cleanup(removeZeroDuration)
+}
+
+func (ctis *ConcurrentTimeIntervalSet) Sort() {
+	ctis.mu.Lock()
+	defer ctis.mu.Unlock()
+
+	ctis.tis.Sort()
+}
+
+// Update runs f with the underlying TimeIntervalSet under the write lock.
+// f must not keep tis after returning.
+func (ctis *ConcurrentTimeIntervalSet) Update(f func(tis *TimeIntervalSet)) {
+	ctis.mu.Lock()
+	defer ctis.mu.Unlock()
+
+	f(ctis.tis)
+}
+
+// AddIfFree adds ti only if it intersects none of the elements,
+// and reports whether ti is added.
+func (ctis *ConcurrentTimeIntervalSet) AddIfFree(ti *TimeInterval) bool {
+	ctis.mu.Lock()
+	defer ctis.mu.Unlock()
+
+	for _, v := range ctis.tis.elements {
+		if v.Intersects(ti) {
+			return false
+		}
+	}
+
+	ctis.tis.Add(ti)
+
+	return true
+}
+
+// SubtractAndReturn removes ti from every element
+// and returns the removed parts.
+func (ctis *ConcurrentTimeIntervalSet) SubtractAndReturn(ti *TimeInterval) *TimeIntervalSet {
+	ctis.mu.Lock()
+	defer ctis.mu.Unlock()
+
+	ret := NewTimeIntervalSet()
+	rest := NewTimeIntervalSet()
+
+	for _, v := range ctis.tis.elements {
+		if !ti.IsZeroDuration() && v.Intersects(ti) {
+			ret.Add(v.Intersection(ti))
+		}
+
+		rest.Merge(v.Subtract(ti))
+	}
+
+	ctis.tis = rest
+
+	return ret
+}
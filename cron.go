@@ -0,0 +1,500 @@
+package timeinterval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears is how far Next() and Prev() look for a fire time
+const cronSearchYears = 100
+
+// CronSchedule
+//
+// A cron expression in a time zone. Fields are
+//
+//	[second] minute hour day-of-month month day-of-week
+//
+// with *, ?, lists (1,2), ranges (1-5), steps (*/15, 5/15, 1-30/2),
+// month names (JAN-DEC) and weekday names (SUN-SAT, 0 or 7 is Sunday).
+// Day of month also takes L (last day), L-n (n days before the last day),
+// nW (the weekday nearest to the day n in the month) and LW (the last weekday),
+// and day of week takes nL (the last weekday n of the month) and n#k (the k-th weekday n).
+// Like Vixie cron, if both day fields are restricted (not starting with * or ?),
+// a day matching either of them fires.
+//
+// The macros @yearly (@annually), @monthly, @weekly, @daily (@midnight) and @hourly are accepted.
+// Wall clock times skipped by a DST transition do not fire, and repeated ones fire once.
+type CronSchedule struct {
+	expr string
+	loc  *time.Location
+
+	seconds uint64
+	minutes uint64
+	hours   uint64
+	dom     uint64
+	months  uint64
+	dow     uint64
+
+	domExtra []func(year, month, day int) bool
+	dowExtra []func(year, month, day int) bool
+
+	domStar bool
+	dowStar bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: cronMonthNames}
+	cronDow    = cronField{name: "day of week", min: 0, max: 7, names: cronWeekdayNames}
+)
+
+// ParseCron parses expr, which fires in loc
+func ParseCron(expr string, loc *time.Location) (*CronSchedule, error) {
+	if loc == nil {
+		panic("nil argument")
+	}
+
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "@") {
+		v, ok := cronMacros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("invalid cron expression %q: unknown macro", expr)
+		}
+		spec = v
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression %q: %d fields instead of 5 or 6", expr, len(fields))
+	}
+
+	ret := &CronSchedule{
+		expr: expr,
+		loc:  loc,
+
+		domStar: strings.HasPrefix(fields[3], "*") || fields[3] == "?",
+		dowStar: strings.HasPrefix(fields[5], "*") || fields[5] == "?",
+	}
+
+	var err error
+
+	for _, v := range []struct {
+		field *cronField
+		s     string
+		bits  *uint64
+	}{
+		{&cronSecond, fields[0], &ret.seconds},
+		{&cronMinute, fields[1], &ret.minutes},
+		{&cronHour, fields[2], &ret.hours},
+		{&cronMonth, fields[4], &ret.months},
+	} {
+		if *v.bits, err = v.field.parse(v.s); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+	}
+
+	if ret.dom, ret.domExtra, err = parseCronDom(fields[3]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+
+	if ret.dow, ret.dowExtra, err = parseCronDow(fields[5]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+
+	return ret, nil
+}
+
+func (cs *CronSchedule) String() string {
+	return cs.expr
+}
+
+func (cs *CronSchedule) Location() *time.Location {
+	return cs.loc
+}
+
+// parse parses a comma separated list of numbers, ranges and steps
+func (f *cronField) parse(s string) (uint64, error) {
+	var ret uint64
+
+	for _, item := range strings.Split(s, ",") {
+		bits, err := f.parseItem(item)
+		if err != nil {
+			return 0, err
+		}
+		ret |= bits
+	}
+
+	return ret, nil
+}
+
+func (f *cronField) parseItem(item string) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("%s: invalid step %q", f.name, item)
+		}
+		step = n
+	}
+
+	var first, last int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		first, last = f.min, f.max
+	default:
+		a, b, isRange := strings.Cut(rangePart, "-")
+
+		var err error
+		if first, err = f.value(a); err != nil {
+			return 0, err
+		}
+
+		switch {
+		case isRange:
+			if last, err = f.value(b); err != nil {
+				return 0, err
+			}
+		case hasStep:
+			// 5/15 is 5-max/15
+			last = f.max
+		default:
+			last = first
+		}
+	}
+
+	if first > last {
+		return 0, fmt.Errorf("%s: invalid range %q", f.name, item)
+	}
+
+	var ret uint64
+	for i := first; i <= last; i += step {
+		ret |= 1 << uint(i)
+	}
+
+	return ret, nil
+}
+
+func (f *cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	ret, err := strconv.Atoi(s)
+	if err != nil || ret < f.min || ret > f.max {
+		return 0, fmt.Errorf("%s: invalid value %q", f.name, s)
+	}
+
+	return ret, nil
+}
+
+func parseCronDom(s string) (uint64, []func(year, month, day int) bool, error) {
+	var bits uint64
+	extra := []func(year, month, day int) bool{}
+
+	for _, item := range strings.Split(s, ",") {
+		switch {
+		case item == "L":
+			extra = append(extra, func(year, month, day int) bool {
+				return day == daysIn(year, month)
+			})
+
+		case item == "LW":
+			extra = append(extra, func(year, month, day int) bool {
+				return day == nearestWeekday(year, month, daysIn(year, month))
+			})
+
+		case strings.HasPrefix(item, "L-"):
+			n, err := strconv.Atoi(item[2:])
+			if err != nil || n < 0 || n > 30 {
+				return 0, nil, fmt.Errorf("%s: invalid value %q", cronDom.name, item)
+			}
+			extra = append(extra, func(year, month, day int) bool {
+				return day == daysIn(year, month)-n
+			})
+
+		case strings.HasSuffix(item, "W"):
+			n, err := cronDom.value(item[:len(item)-1])
+			if err != nil {
+				return 0, nil, err
+			}
+			extra = append(extra, func(year, month, day int) bool {
+				return n <= daysIn(year, month) && day == nearestWeekday(year, month, n)
+			})
+
+		default:
+			v, err := cronDom.parseItem(item)
+			if err != nil {
+				return 0, nil, err
+			}
+			bits |= v
+		}
+	}
+
+	return bits, extra, nil
+}
+
+func parseCronDow(s string) (uint64, []func(year, month, day int) bool, error) {
+	var bits uint64
+	extra := []func(year, month, day int) bool{}
+
+	for _, item := range strings.Split(s, ",") {
+		weekday, nth, isNth := strings.Cut(item, "#")
+
+		switch {
+		case isNth:
+			wd, err := cronDow.value(weekday)
+			if err != nil {
+				return 0, nil, err
+			}
+			k, err := strconv.Atoi(nth)
+			if err != nil || k < 1 || k > 5 {
+				return 0, nil, fmt.Errorf("%s: invalid value %q", cronDow.name, item)
+			}
+			extra = append(extra, func(year, month, day int) bool {
+				return weekdayOf(year, month, day) == wd%7 && (day-1)/7+1 == k
+			})
+
+		case len(item) > 1 && strings.HasSuffix(item, "L"):
+			wd, err := cronDow.value(item[:len(item)-1])
+			if err != nil {
+				return 0, nil, err
+			}
+			extra = append(extra, func(year, month, day int) bool {
+				return weekdayOf(year, month, day) == wd%7 && day+7 > daysIn(year, month)
+			})
+
+		default:
+			v, err := cronDow.parseItem(item)
+			if err != nil {
+				return 0, nil, err
+			}
+			bits |= v
+		}
+	}
+
+	// 7 is Sunday
+	if bits&(1<<7) != 0 {
+		bits = bits&^(1<<7) | 1
+	}
+
+	return bits, extra, nil
+}
+
+func daysIn(year, month int) int {
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func weekdayOf(year, month, day int) int {
+	return int(time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Weekday())
+}
+
+// nearestWeekday returns the weekday (Monday to Friday) nearest to day in the same month
+func nearestWeekday(year, month, day int) int {
+	switch weekdayOf(year, month, day) {
+	case int(time.Saturday):
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case int(time.Sunday):
+		if day == daysIn(year, month) {
+			return day - 2
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
+
+func (cs *CronSchedule) matchDay(year, month, day int) bool {
+	if cs.months&(1<<uint(month)) == 0 {
+		return false
+	}
+
+	dom := cs.dom&(1<<uint(day)) != 0
+	for _, f := range cs.domExtra {
+		dom = dom || f(year, month, day)
+	}
+
+	dow := cs.dow&(1<<uint(weekdayOf(year, month, day))) != 0
+	for _, f := range cs.dowExtra {
+		dow = dow || f(year, month, day)
+	}
+
+	if cs.domStar || cs.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// fireOn returns the first (or the last if backward) fire time on the date
+// which is after (or before if backward) t, or nil
+func (cs *CronSchedule) fireOn(year, month, day int, t time.Time, backward bool) *time.Time {
+	order := func(i, n int) int {
+		if backward {
+			return n - 1 - i
+		}
+		return i
+	}
+
+	tYear, tMonth, tDay := t.Date()
+	sameDay := tYear == year && int(tMonth) == month && tDay == day
+
+	// wall clock of t, to skip the times obviously before (or after) t
+	wall := t.Hour()*3600 + t.Minute()*60 + t.Second()
+
+	for i := 0; i < 24; i++ {
+		h := order(i, 24)
+		if cs.hours&(1<<uint(h)) == 0 {
+			continue
+		}
+		if sameDay && (!backward && (h+1)*3600 <= wall || backward && h*3600 > wall) {
+			continue
+		}
+
+		for j := 0; j < 60; j++ {
+			m := order(j, 60)
+			if cs.minutes&(1<<uint(m)) == 0 {
+				continue
+			}
+
+			for k := 0; k < 60; k++ {
+				s := order(k, 60)
+				if cs.seconds&(1<<uint(s)) == 0 {
+					continue
+				}
+
+				ret := time.Date(year, time.Month(month), day, h, m, s, 0, cs.loc)
+				if ret.Hour() != h || ret.Minute() != m {
+					// skipped by DST
+					continue
+				}
+
+				if !backward && ret.After(t) || backward && ret.Before(t) {
+					return &ret
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cs *CronSchedule) search(tp *TimePoint, backward bool) *TimePoint {
+	t := tp.t.In(cs.loc)
+
+	year, month, day := t.Date()
+	d := NewDate(year, int(month), day)
+
+	delta := 1
+	if backward {
+		delta = -1
+	}
+
+	for i := 0; i < cronSearchYears*366; i++ {
+		if cs.matchDay(d.year, d.month, d.day) {
+			if ret := cs.fireOn(d.year, d.month, d.day, t, backward); ret != nil {
+				return FromTime(*ret)
+			}
+		}
+
+		d = d.AddDays(delta)
+	}
+
+	return nil
+}
+
+// Next returns the first fire time after tp, or nil if there is none in 100 years
+func (cs *CronSchedule) Next(tp *TimePoint) *TimePoint {
+	return cs.search(tp, false)
+}
+
+// Prev returns the last fire time before tp, or nil if there is none in 100 years
+func (cs *CronSchedule) Prev(tp *TimePoint) *TimePoint {
+	return cs.search(tp, true)
+}
+
+// TimePoints returns the fire times in [bound.Start(), bound.End())
+func (cs *CronSchedule) TimePoints(bound *TimeInterval) []*TimePoint {
+	ret := []*TimePoint{}
+
+	if bound.IsEmpty() {
+		return ret
+	}
+
+	tp := cs.Next(FromTime(bound.start.t.Add(-1)))
+	for tp != nil && tp.Before(bound.end) {
+		ret = append(ret, tp)
+		tp = cs.Next(tp)
+	}
+
+	return ret
+}
+
+// TimeIntervals returns the windows of d starting at each fire time,
+// which intersect bound, clipped to bound.
+// Windows are not merged even if they overlap.
+func (cs *CronSchedule) TimeIntervals(bound *TimeInterval, d time.Duration) *TimeIntervalSet {
+	if d < 0 {
+		panic(fmt.Sprint("invalid duration: ", d))
+	}
+
+	ret := NewTimeIntervalSet()
+
+	if bound.IsEmpty() {
+		return ret
+	}
+
+	// windows started before bound and still open
+	running := []*TimeInterval{}
+	for tp := cs.Prev(bound.start); tp != nil && tp.t.Add(d).After(bound.start.t); tp = cs.Prev(tp) {
+		running = append(running, NewTimeInterval(bound.start, TimePointMin(FromTime(tp.t.Add(d)), bound.end)))
+	}
+	for i := len(running) - 1; i >= 0; i-- {
+		ret.Add(running[i])
+	}
+
+	for _, tp := range cs.TimePoints(bound) {
+		ret.Add(NewTimeInterval(tp, TimePointMin(FromTime(tp.t.Add(d)), bound.end)))
+	}
+
+	return ret
+}
@@ -0,0 +1,316 @@
+package timeinterval
+
+import (
+	"fmt"
+	"time"
+)
+
+// Date
+type Date struct {
+	year  int
+	month int
+	day   int
+}
+
+func (d *Date) Year() int {
+	return d.year
+}
+
+func (d *Date) Month() int {
+	return d.month
+}
+
+func (d *Date) Day() int {
+	return d.day
+}
+
+func NewDate(year, month, day int) *Date {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+
+	_year, _month, _day := t.Date()
+
+	ret := &Date{
+		year:  _year,
+		month: int(_month), // [1, 12]
+		day:   _day,
+	}
+
+	return ret
+}
+
+func (d *Date) Copy() *Date {
+	// Date is immutable
+	return d
+}
+
+func (d *Date) date(loc *time.Location) time.Time {
+	return time.Date(d.year, time.Month(d.month), d.day, 0, 0, 0, 0, loc)
+}
+
+func (d *Date) Weekday() time.Weekday {
+	return d.date(time.UTC).Weekday()
+}
+
+func (d *Date) AddDays(days int) *Date {
+	return NewDate(d.year, d.month, d.day+days)
+}
+
+func (d *Date) Compare(d2 *Date) CompareResult {
+	v := d.date(time.UTC).Compare(d2.date(time.UTC))
+	switch v {
+	case -1:
+		return Before
+	case 0:
+		return Equal
+	case +1:
+		return After
+	default:
+		panic(fmt.Sprint("invalid return value from time.Time.Compare():", v))
+	}
+}
+
+func (d *Date) Equal(d2 *Date) bool {
+	return d.Compare(d2) == Equal
+}
+
+func (d *Date) Before(d2 *Date) bool {
+	return d.Compare(d2) == Before
+}
+
+func (d *Date) After(d2 *Date) bool {
+	return d.Compare(d2) == After
+}
+
+// Start returns the midnight starting d in loc
+func (d *Date) Start(loc *time.Location) *TimePoint {
+	return FromTime(d.date(loc))
+}
+
+// TimeInterval returns the whole day d in loc, from its midnight to the next midnight
+func (d *Date) TimeInterval(loc *time.Location) *TimeInterval {
+	return NewTimeInterval(d.Start(loc), d.AddDays(1).Start(loc))
+}
+
+// DateInterval
+//
+// Both first and last are included, i.e. "2024-03-01 to 2024-03-15"
+// is NewDateInterval(NewDate(2024, 3, 1), NewDate(2024, 3, 15)) and has 15 days.
+type DateInterval struct {
+	first *Date
+	last  *Date
+}
+
+func (di *DateInterval) First() *Date {
+	return di.first
+}
+
+func (di *DateInterval) Last() *Date {
+	return di.last
+}
+
+func NewDateInterval(first, last *Date) *DateInterval {
+	if first == nil || last == nil {
+		panic("nil argument")
+	}
+
+	if last.Before(first) {
+		panic(fmt.Sprintf("last is before first: first: %v, last: %v", first, last))
+	}
+
+	ret := &DateInterval{
+		first: first,
+		last:  last,
+	}
+
+	return ret
+}
+
+func (di *DateInterval) Days() int {
+	return int((di.last.date(time.UTC).Unix()-di.first.date(time.UTC).Unix())/(24*60*60)) + 1
+}
+
+func (di *DateInterval) Dates() []*Date {
+	ret := []*Date{}
+
+	for d := di.first; !d.After(di.last); d = d.AddDays(1) {
+		ret = append(ret, d)
+	}
+
+	return ret
+}
+
+func (di *DateInterval) Equal(di2 *DateInterval) bool {
+	return di.first.Equal(di2.first) && di.last.Equal(di2.last)
+}
+
+func (di *DateInterval) Has(d *Date) bool {
+	return (!d.Before(di.first)) && (!d.After(di.last))
+}
+
+// TimeInterval returns the interval from the midnight starting first
+// to the midnight ending last in loc
+func (di *DateInterval) TimeInterval(loc *time.Location) *TimeInterval {
+	return NewTimeInterval(di.first.Start(loc), di.last.AddDays(1).Start(loc))
+}
+
+// TimeOfDay
+type TimeOfDay struct {
+	hour       int
+	minute     int
+	second     int
+	nanosecond int
+}
+
+func (tod *TimeOfDay) Hour() int {
+	return tod.hour
+}
+
+func (tod *TimeOfDay) Minute() int {
+	return tod.minute
+}
+
+func (tod *TimeOfDay) Second() int {
+	return tod.second
+}
+
+func (tod *TimeOfDay) Nanosecond() int {
+	return tod.nanosecond
+}
+
+// NewTimeOfDay panics if an argument is out of range.
+// Unlike NewTimePoint, it does not normalize, e.g. 24:00 is not allowed.
+func NewTimeOfDay(hour, minute, sec, nsec int) *TimeOfDay {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 || sec < 0 || sec > 59 || nsec < 0 || nsec > 999999999 {
+		panic(fmt.Sprintf("invalid time of day: %d:%d:%d.%d", hour, minute, sec, nsec))
+	}
+
+	ret := &TimeOfDay{
+		hour:       hour,
+		minute:     minute,
+		second:     sec,
+		nanosecond: nsec,
+	}
+
+	return ret
+}
+
+func (tod *TimeOfDay) Copy() *TimeOfDay {
+	// TimeOfDay is immutable
+	return tod
+}
+
+// sinceMidnight returns the nominal duration since midnight, ignoring DST
+func (tod *TimeOfDay) sinceMidnight() time.Duration {
+	return time.Duration(tod.hour)*time.Hour +
+		time.Duration(tod.minute)*time.Minute +
+		time.Duration(tod.second)*time.Second +
+		time.Duration(tod.nanosecond)
+}
+
+func (tod *TimeOfDay) Compare(tod2 *TimeOfDay) CompareResult {
+	a, b := tod.sinceMidnight(), tod2.sinceMidnight()
+	switch {
+	case a < b:
+		return Before
+	case a > b:
+		return After
+	default:
+		return Equal
+	}
+}
+
+func (tod *TimeOfDay) Equal(tod2 *TimeOfDay) bool {
+	return tod.Compare(tod2) == Equal
+}
+
+func (tod *TimeOfDay) Before(tod2 *TimeOfDay) bool {
+	return tod.Compare(tod2) == Before
+}
+
+func (tod *TimeOfDay) After(tod2 *TimeOfDay) bool {
+	return tod.Compare(tod2) == After
+}
+
+// On returns the TimePoint of tod on the date d in loc
+func (tod *TimeOfDay) On(d *Date, loc *time.Location) *TimePoint {
+	return FromTime(time.Date(d.year, time.Month(d.month), d.day, tod.hour, tod.minute, tod.second, tod.nanosecond, loc))
+}
+
+// TimeOfDayInterval
+//
+// From start (inclusive) to end (exclusive) every day.
+// If end is not after start, it crosses midnight and ends on the next day,
+// e.g. 22:00-06:00 is 8 hours and 00:00-00:00 is a whole day.
+type TimeOfDayInterval struct {
+	start *TimeOfDay
+	end   *TimeOfDay
+}
+
+func (todi *TimeOfDayInterval) Start() *TimeOfDay {
+	return todi.start
+}
+
+func (todi *TimeOfDayInterval) End() *TimeOfDay {
+	return todi.end
+}
+
+func NewTimeOfDayInterval(start, end *TimeOfDay) *TimeOfDayInterval {
+	if start == nil || end == nil {
+		panic("nil argument")
+	}
+
+	ret := &TimeOfDayInterval{
+		start: start,
+		end:   end,
+	}
+
+	return ret
+}
+
+func (todi *TimeOfDayInterval) CrossesMidnight() bool {
+	return !todi.end.After(todi.start)
+}
+
+// Duration returns the nominal duration, ignoring DST.
+// Use On() for the actual duration on a specific date.
+func (todi *TimeOfDayInterval) Duration() time.Duration {
+	ret := todi.end.sinceMidnight() - todi.start.sinceMidnight()
+	if todi.CrossesMidnight() {
+		ret += time.Hour * 24
+	}
+
+	return ret
+}
+
+func (todi *TimeOfDayInterval) Equal(todi2 *TimeOfDayInterval) bool {
+	return todi.start.Equal(todi2.start) && todi.end.Equal(todi2.end)
+}
+
+func (todi *TimeOfDayInterval) Has(tod *TimeOfDay) bool {
+	if todi.CrossesMidnight() {
+		return !tod.Before(todi.start) || tod.Before(todi.end)
+	}
+
+	return !tod.Before(todi.start) && tod.Before(todi.end)
+}
+
+// On returns the TimeInterval starting on the date d in loc
+func (todi *TimeOfDayInterval) On(d *Date, loc *time.Location) *TimeInterval {
+	endDate := d
+	if todi.CrossesMidnight() {
+		endDate = d.AddDays(1)
+	}
+
+	return NewTimeInterval(todi.start.On(d, loc), todi.end.On(endDate, loc))
+}
+
+// TimeIntervals returns the TimeIntervals starting on each date of di in loc
+func (todi *TimeOfDayInterval) TimeIntervals(di *DateInterval, loc *time.Location) *TimeIntervalSet {
+	ret := NewTimeIntervalSet()
+
+	for _, d := range di.Dates() {
+		ret.Add(todi.On(d, loc))
+	}
+
+	return ret
+}
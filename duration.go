@@ -0,0 +1,226 @@
+package timeinterval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrDurationOverflow = errors.New("timeinterval: duration overflows time.Duration")
+
+// WideDuration
+//
+// time.Duration overflows at about 292 years.
+// WideDuration keeps seconds and nanoseconds separately,
+// so it can hold any difference between two TimePoints.
+type WideDuration struct {
+	seconds     int64
+	nanoseconds int64 // [0, 999999999]
+}
+
+func NewWideDuration(seconds, nanoseconds int64) WideDuration {
+	s, overflow := addInt64(seconds, nanoseconds/int64(time.Second))
+	if overflow {
+		panic(fmt.Sprint("WideDuration overflow: ", seconds, nanoseconds))
+	}
+	seconds = s
+	nanoseconds %= int64(time.Second)
+
+	if nanoseconds < 0 {
+		if seconds == math.MinInt64 {
+			panic(fmt.Sprint("WideDuration overflow: ", seconds, nanoseconds))
+		}
+		seconds--
+		nanoseconds += int64(time.Second)
+	}
+
+	ret := WideDuration{
+		seconds:     seconds,
+		nanoseconds: nanoseconds,
+	}
+
+	return ret
+}
+
+func WideDurationOf(d time.Duration) WideDuration {
+	return NewWideDuration(0, int64(d))
+}
+
+// Seconds returns the whole seconds, rounded down
+func (wd WideDuration) Seconds() int64 {
+	return wd.seconds
+}
+
+// Nanoseconds returns the nanoseconds remaining after Seconds(), in [0, 999999999]
+func (wd WideDuration) Nanoseconds() int64 {
+	return wd.nanoseconds
+}
+
+func (wd WideDuration) Hours() float64 {
+	return (float64(wd.seconds) + float64(wd.nanoseconds)/float64(time.Second)) / 3600
+}
+
+func (wd WideDuration) IsZero() bool {
+	return wd.seconds == 0 && wd.nanoseconds == 0
+}
+
+func (wd WideDuration) Add(wd2 WideDuration) WideDuration {
+	seconds, overflow := addInt64(wd.seconds, wd2.seconds)
+	if overflow {
+		panic(fmt.Sprint("WideDuration overflow: ", wd, " + ", wd2))
+	}
+
+	return NewWideDuration(seconds, wd.nanoseconds+wd2.nanoseconds)
+}
+
+func (wd WideDuration) Compare(wd2 WideDuration) CompareResult {
+	switch {
+	case wd.seconds < wd2.seconds:
+		return Before
+	case wd.seconds > wd2.seconds:
+		return After
+	case wd.nanoseconds < wd2.nanoseconds:
+		return Before
+	case wd.nanoseconds > wd2.nanoseconds:
+		return After
+	default:
+		return Equal
+	}
+}
+
+// Duration converts wd to time.Duration.
+// ok is false if wd does not fit in time.Duration.
+func (wd WideDuration) Duration() (d time.Duration, ok bool) {
+	const maxSeconds = math.MaxInt64 / int64(time.Second)
+
+	seconds, nanoseconds := wd.seconds, wd.nanoseconds
+	if seconds < 0 && nanoseconds > 0 {
+		seconds++
+		nanoseconds -= int64(time.Second)
+	}
+
+	if seconds > maxSeconds || seconds < -maxSeconds {
+		return time.Duration(0), false
+	}
+
+	ret, overflow := addInt64(seconds*int64(time.Second), nanoseconds)
+	if overflow {
+		return time.Duration(0), false
+	}
+
+	return time.Duration(ret), true
+}
+
+// Saturated converts wd to time.Duration, clamping to the representable range
+func (wd WideDuration) Saturated() time.Duration {
+	if d, ok := wd.Duration(); ok {
+		return d
+	}
+
+	if wd.seconds < 0 {
+		return time.Duration(math.MinInt64)
+	}
+	return time.Duration(math.MaxInt64)
+}
+
+func (wd WideDuration) String() string {
+	if d, ok := wd.Duration(); ok {
+		return d.String()
+	}
+
+	return fmt.Sprintf("%.0fh", wd.Hours())
+}
+
+// WideDiff returns the absolute difference between tp and tp2 without overflow
+func (tp *TimePoint) WideDiff(tp2 *TimePoint) WideDuration {
+	a, b := tp, tp2
+	if a.Before(b) {
+		a, b = b, a
+	}
+
+	at, bt := a.t, b.t
+	if a.scale == TimeScaleUTC || b.scale == TimeScaleUTC {
+		at, bt = a.tai(), b.tai()
+	}
+
+	return NewWideDuration(at.Unix()-bt.Unix(), int64(at.Nanosecond()-bt.Nanosecond()))
+}
+
+func (ti *TimeInterval) WideDuration() WideDuration {
+	if ti.IsEmpty() {
+		return WideDuration{}
+	}
+
+	return ti.start.WideDiff(ti.end)
+}
+
+// DurationChecked returns ErrDurationOverflow instead of saturating as Duration() does
+func (ti *TimeInterval) DurationChecked() (time.Duration, error) {
+	if d, ok := ti.WideDuration().Duration(); ok {
+		return d, nil
+	}
+
+	return time.Duration(0), ErrDurationOverflow
+}
+
+func (tis *TimeIntervalSet) WideDuration() WideDuration {
+	ret := WideDuration{}
+
+	for _, ti := range tis.elements {
+		ret = ret.Add(ti.WideDuration())
+	}
+
+	return ret
+}
+
+// DurationChecked returns ErrDurationOverflow instead of saturating as Duration() does
+func (tis *TimeIntervalSet) DurationChecked() (time.Duration, error) {
+	if d, ok := tis.WideDuration().Duration(); ok {
+		return d, nil
+	}
+
+	return time.Duration(0), ErrDurationOverflow
+}
+
+// addSaturated returns a + b, clamped to the range of time.Duration
+func addSaturated(a, b time.Duration) time.Duration {
+	ret, overflow := addInt64(int64(a), int64(b))
+	if !overflow {
+		return time.Duration(ret)
+	}
+
+	if b < 0 {
+		return time.Duration(math.MinInt64)
+	}
+	return time.Duration(math.MaxInt64)
+}
+
+// subSaturated returns a - b, clamped to the range of time.Duration
+func subSaturated(a, b time.Duration) time.Duration {
+	ret, overflow := subInt64(int64(a), int64(b))
+	if !overflow {
+		return time.Duration(ret)
+	}
+
+	if b > 0 {
+		return time.Duration(math.MinInt64)
+	}
+	return time.Duration(math.MaxInt64)
+}
+
+func addInt64(a, b int64) (int64, bool) {
+	ret := a + b
+
+	overflow := (b > 0 && ret < a) || (b < 0 && ret > a)
+
+	return ret, overflow
+}
+
+func subInt64(a, b int64) (int64, bool) {
+	ret := a - b
+
+	overflow := (b > 0 && ret > a) || (b < 0 && ret < a)
+
+	return ret, overflow
+}
@@ -0,0 +1,405 @@
+package timeinterval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatStyle
+type FormatStyle int
+
+const (
+	// FormatISO is ISO 8601, e.g. 2024-06-03T09:00:00Z/2024-06-03T10:30:00Z
+	FormatISO FormatStyle = iota
+	// FormatCompact is e.g. Mon 3 Jun, 09:00–10:30
+	FormatCompact
+	// FormatVerbose is e.g. Monday 3 June 2024, 09:00:00–10:30:00
+	FormatVerbose
+)
+
+// Locale
+//
+// Names and layouts used by FormatCompact and FormatVerbose.
+// Date layouts can have {Y} (year), {M} (month name), {D} (day of month) and {W} (weekday name),
+// where the short names are used by FormatCompact.
+type Locale struct {
+	Months        [12]string
+	ShortMonths   [12]string
+	Weekdays      [7]string // from Sunday, as time.Weekday
+	ShortWeekdays [7]string
+
+	ShortDate     string
+	ShortDateYear string
+	LongDate      string
+	LongDateYear  string
+
+	// YearFirst keeps the year on the start, instead of the end,
+	// when an interval within a year is collapsed in FormatVerbose
+	YearFirst bool
+
+	DateTimeSeparator  string // between a date and a time of day
+	RangeSeparator     string // between times of the same day
+	LongRangeSeparator string // between time points of different days
+
+	// appended to the numbers of days, hours, minutes and seconds
+	ShortUnits [4]string
+	Units      [4][2]string // singular, plural
+
+	DecimalSeparator string
+}
+
+var LocaleEnglish = &Locale{
+	Months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	ShortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	ShortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+
+	ShortDate:     "{W} {D} {M}",
+	ShortDateYear: "{W} {D} {M} {Y}",
+	LongDate:      "{W} {D} {M}",
+	LongDateYear:  "{W} {D} {M} {Y}",
+
+	DateTimeSeparator:  ", ",
+	RangeSeparator:     "–",
+	LongRangeSeparator: " – ",
+
+	ShortUnits:       [4]string{" d", " h", " min", " s"},
+	Units:            [4][2]string{{" day", " days"}, {" hour", " hours"}, {" minute", " minutes"}, {" second", " seconds"}},
+	DecimalSeparator: ".",
+}
+
+var LocaleGerman = &Locale{
+	Months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	ShortMonths:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+	Weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	ShortWeekdays: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+
+	ShortDate:     "{W}, {D}. {M}",
+	ShortDateYear: "{W}, {D}. {M} {Y}",
+	LongDate:      "{W}, {D}. {M}",
+	LongDateYear:  "{W}, {D}. {M} {Y}",
+
+	DateTimeSeparator:  ", ",
+	RangeSeparator:     "–",
+	LongRangeSeparator: " – ",
+
+	ShortUnits:       [4]string{" T.", " Std.", " Min.", " Sek."},
+	Units:            [4][2]string{{" Tag", " Tage"}, {" Stunde", " Stunden"}, {" Minute", " Minuten"}, {" Sekunde", " Sekunden"}},
+	DecimalSeparator: ",",
+}
+
+var LocaleFrench = &Locale{
+	Months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	ShortMonths:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+	Weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	ShortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+
+	ShortDate:     "{W} {D} {M}",
+	ShortDateYear: "{W} {D} {M} {Y}",
+	LongDate:      "{W} {D} {M}",
+	LongDateYear:  "{W} {D} {M} {Y}",
+
+	DateTimeSeparator:  ", ",
+	RangeSeparator:     "–",
+	LongRangeSeparator: " – ",
+
+	ShortUnits:       [4]string{" j", " h", " min", " s"},
+	Units:            [4][2]string{{" jour", " jours"}, {" heure", " heures"}, {" minute", " minutes"}, {" seconde", " secondes"}},
+	DecimalSeparator: ",",
+}
+
+var LocaleSpanish = &Locale{
+	Months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	ShortMonths:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+	Weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+	ShortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+
+	ShortDate:     "{W} {D} {M}",
+	ShortDateYear: "{W} {D} {M} {Y}",
+	LongDate:      "{W}, {D} de {M}",
+	LongDateYear:  "{W}, {D} de {M} de {Y}",
+
+	DateTimeSeparator:  ", ",
+	RangeSeparator:     "–",
+	LongRangeSeparator: " – ",
+
+	ShortUnits:       [4]string{" d", " h", " min", " s"},
+	Units:            [4][2]string{{" día", " días"}, {" hora", " horas"}, {" minuto", " minutos"}, {" segundo", " segundos"}},
+	DecimalSeparator: ",",
+}
+
+var LocaleKorean = &Locale{
+	Months:        [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+	ShortMonths:   [12]string{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"},
+	Weekdays:      [7]string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"},
+	ShortWeekdays: [7]string{"일", "월", "화", "수", "목", "금", "토"},
+
+	ShortDate:     "{M} {D}일 ({W})",
+	ShortDateYear: "{Y}년 {M} {D}일 ({W})",
+	LongDate:      "{M} {D}일 {W}",
+	LongDateYear:  "{Y}년 {M} {D}일 {W}",
+	YearFirst:     true,
+
+	DateTimeSeparator:  " ",
+	RangeSeparator:     "~",
+	LongRangeSeparator: " ~ ",
+
+	ShortUnits:       [4]string{"일", "시간", "분", "초"},
+	Units:            [4][2]string{{"일", "일"}, {"시간", "시간"}, {"분", "분"}, {"초", "초"}},
+	DecimalSeparator: ".",
+}
+
+// DefaultLocale is used by the fmt verbs %c and %+v
+var DefaultLocale = LocaleEnglish
+
+func (l *Locale) FormatTimePoint(tp *TimePoint, style FormatStyle) string {
+	switch style {
+	case FormatISO:
+		return tp.iso()
+	case FormatCompact, FormatVerbose:
+		return l.dateTime(tp, style, style == FormatVerbose)
+	default:
+		panic(fmt.Sprint("invalid format style: ", style))
+	}
+}
+
+// FormatTimeInterval omits the date parts of the end shared with the start.
+// The empty TimeInterval is formatted as "∅" in any style.
+func (l *Locale) FormatTimeInterval(ti *TimeInterval, style FormatStyle) string {
+	switch style {
+	case FormatISO, FormatCompact, FormatVerbose:
+	default:
+		panic(fmt.Sprint("invalid format style: ", style))
+	}
+
+	if ti.IsEmpty() {
+		return "∅"
+	}
+
+	if style == FormatISO {
+		return ti.start.iso() + "/" + ti.end.iso()
+	}
+
+	start, end := ti.start, ti.end
+	verbose := style == FormatVerbose
+
+	if start.year != end.year {
+		return l.dateTime(start, style, true) + l.LongRangeSeparator + l.dateTime(end, style, true)
+	}
+
+	if start.month == end.month && start.day == end.day {
+		return l.dateTime(start, style, verbose) + l.RangeSeparator + l.clock(end, style)
+	}
+
+	startYear, endYear := false, false
+	if verbose {
+		startYear, endYear = l.YearFirst, !l.YearFirst
+	}
+
+	return l.dateTime(start, style, startYear) + l.LongRangeSeparator + l.dateTime(end, style, endYear)
+}
+
+func (l *Locale) FormatTimeIntervalSet(tis *TimeIntervalSet, style FormatStyle) string {
+	sep := "; "
+	if style == FormatISO {
+		sep = ", "
+	}
+
+	s := make([]string, len(tis.elements))
+	for i, v := range tis.elements {
+		s[i] = l.FormatTimeInterval(v, style)
+	}
+
+	return "[" + strings.Join(s, sep) + "]"
+}
+
+// FormatDuration formats d as e.g. PT2H15M, 2 h 15 min or 2 hours 15 minutes.
+// Zero units are omitted.
+func (l *Locale) FormatDuration(d time.Duration, style FormatStyle) string {
+	sign := ""
+	abs := uint64(d)
+	if d < 0 {
+		sign = "-"
+		abs = -abs
+	}
+
+	const day = uint64(24 * time.Hour)
+
+	units := [4]uint64{
+		abs / day,
+		abs % day / uint64(time.Hour),
+		abs % uint64(time.Hour) / uint64(time.Minute),
+		abs % uint64(time.Minute) / uint64(time.Second),
+	}
+	nsec := int(abs % uint64(time.Second))
+
+	switch style {
+	case FormatISO:
+		// days are not calendar days, so they are written as hours
+		units[1] += units[0] * 24
+		units[0] = 0
+	case FormatCompact, FormatVerbose:
+	default:
+		panic(fmt.Sprint("invalid format style: ", style))
+	}
+
+	parts := []string{}
+	for i, v := range units {
+		var frac string
+		if i == 3 {
+			frac = fraction(nsec)
+		}
+
+		// zero duration is written as 0 seconds
+		if v == 0 && frac == "" && (i < 3 || len(parts) > 0) {
+			continue
+		}
+
+		n := strconv.FormatUint(v, 10)
+
+		switch {
+		case style == FormatISO:
+			if frac != "" {
+				n += "." + frac
+			}
+			parts = append(parts, n+string("DHMS"[i]))
+		case style == FormatCompact:
+			if frac != "" {
+				n += l.DecimalSeparator + frac
+			}
+			parts = append(parts, n+l.ShortUnits[i])
+		case v == 1 && frac == "":
+			parts = append(parts, n+l.Units[i][0])
+		default:
+			if frac != "" {
+				n += l.DecimalSeparator + frac
+			}
+			parts = append(parts, n+l.Units[i][1])
+		}
+	}
+
+	if style == FormatISO {
+		return sign + "PT" + strings.Join(parts, "")
+	}
+
+	return sign + strings.Join(parts, " ")
+}
+
+// fraction returns the digits of nsec after the decimal point, without trailing zeros
+func fraction(nsec int) string {
+	if nsec == 0 {
+		return ""
+	}
+
+	return strings.TrimRight(fmt.Sprintf("%09d", nsec), "0")
+}
+
+// iso is RFC 3339 in UTC, which can have 60 as the second
+func (tp *TimePoint) iso() string {
+	ret := fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02d", tp.year, tp.month, tp.day, tp.hour, tp.minute, tp.second)
+	if frac := fraction(tp.nanosecond); frac != "" {
+		ret += "." + frac
+	}
+
+	return ret + "Z"
+}
+
+func (l *Locale) dateTime(tp *TimePoint, style FormatStyle, withYear bool) string {
+	return l.date(tp, style, withYear) + l.DateTimeSeparator + l.clock(tp, style)
+}
+
+func (l *Locale) date(tp *TimePoint, style FormatStyle, withYear bool) string {
+	weekday := time.Date(tp.year, time.Month(tp.month), tp.day, 0, 0, 0, 0, time.UTC).Weekday()
+
+	var layout, month, weekdayName string
+	if style == FormatVerbose {
+		layout, month, weekdayName = l.LongDate, l.Months[tp.month-1], l.Weekdays[weekday]
+		if withYear {
+			layout = l.LongDateYear
+		}
+	} else {
+		layout, month, weekdayName = l.ShortDate, l.ShortMonths[tp.month-1], l.ShortWeekdays[weekday]
+		if withYear {
+			layout = l.ShortDateYear
+		}
+	}
+
+	r := strings.NewReplacer(
+		"{Y}", strconv.Itoa(tp.year),
+		"{M}", month,
+		"{D}", strconv.Itoa(tp.day),
+		"{W}", weekdayName,
+	)
+
+	return r.Replace(layout)
+}
+
+// clock is hh:mm in FormatCompact, if the seconds are zero, and hh:mm:ss otherwise
+func (l *Locale) clock(tp *TimePoint, style FormatStyle) string {
+	frac := fraction(tp.nanosecond)
+
+	if style == FormatCompact && tp.second == 0 && frac == "" {
+		return fmt.Sprintf("%02d:%02d", tp.hour, tp.minute)
+	}
+
+	ret := fmt.Sprintf("%02d:%02d:%02d", tp.hour, tp.minute, tp.second)
+	if frac != "" {
+		ret += l.DecimalSeparator + frac
+	}
+
+	return ret
+}
+
+func (tp *TimePoint) String() string {
+	return tp.iso()
+}
+
+func (ti *TimeInterval) String() string {
+	return DefaultLocale.FormatTimeInterval(ti, FormatISO)
+}
+
+func (tis *TimeIntervalSet) String() string {
+	return DefaultLocale.FormatTimeIntervalSet(tis, FormatISO)
+}
+
+// Format implements fmt.Formatter.
+// %v and %s are ISO 8601 (same as String()), %c is FormatCompact
+// and %+v is FormatVerbose, in DefaultLocale. %q is the quoted %s.
+func (tp *TimePoint) Format(f fmt.State, verb rune) {
+	format(f, verb, "*timeinterval.TimePoint", func(style FormatStyle) string {
+		return DefaultLocale.FormatTimePoint(tp, style)
+	})
+}
+
+// Format implements fmt.Formatter, see TimePoint.Format()
+func (ti *TimeInterval) Format(f fmt.State, verb rune) {
+	format(f, verb, "*timeinterval.TimeInterval", func(style FormatStyle) string {
+		return DefaultLocale.FormatTimeInterval(ti, style)
+	})
+}
+
+// Format implements fmt.Formatter, see TimePoint.Format()
+func (tis *TimeIntervalSet) Format(f fmt.State, verb rune) {
+	format(f, verb, "*timeinterval.TimeIntervalSet", func(style FormatStyle) string {
+		return DefaultLocale.FormatTimeIntervalSet(tis, style)
+	})
+}
+
+func format(f fmt.State, verb rune, typeName string, s func(style FormatStyle) string) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			fmt.Fprintf(f, fmt.FormatString(f, 's'), s(FormatVerbose))
+		} else {
+			fmt.Fprintf(f, fmt.FormatString(f, 's'), s(FormatISO))
+		}
+	case 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), s(FormatISO))
+	case 'c':
+		fmt.Fprintf(f, fmt.FormatString(f, 's'), s(FormatCompact))
+	default:
+		fmt.Fprintf(f, "%%!%c(%s=%s)", verb, typeName, s(FormatISO))
+	}
+}
@@ -2,7 +2,10 @@ module github.com/iloy/timeinterval
 
 go 1.22
 
-require github.com/stretchr/testify v1.8.4
+require (
+	github.com/stretchr/testify v1.8.4
+	google.golang.org/protobuf v1.34.2
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
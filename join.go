@@ -0,0 +1,156 @@
+package timeinterval
+
+import (
+	"fmt"
+)
+
+// LabeledTimeInterval is a TimeInterval with a label, e.g. the ID of an event
+type LabeledTimeInterval[T any] struct {
+	Interval *TimeInterval
+	Label    T
+}
+
+type JoinType int
+
+const (
+	// InnerJoin returns the overlapping pairs
+	InnerJoin JoinType = iota
+	// LeftJoin returns the overlapping pairs,
+	// and the left elements overlapping nothing with nil Right
+	LeftJoin
+	// AntiJoin returns only the left elements overlapping nothing, with nil Right
+	AntiJoin
+)
+
+// JoinPair
+type JoinPair[L, R any] struct {
+	Left  LabeledTimeInterval[L]
+	Right *LabeledTimeInterval[R] // nil if Left overlaps nothing
+
+	// Interval is the intersection of Left and Right, never empty as they Intersects().
+	// nil if Right is nil
+	Interval *TimeInterval
+}
+
+// TemporalJoin joins left and right, both sorted by the start, by Intersects().
+// The empty elements can be anywhere, and overlap nothing.
+//
+// The result is in the order of left, and the pairs of a left element are in the order of right.
+// It takes O(n+m+k) time for k overlapping pairs, by sweeping both with the elements not ended yet.
+func TemporalJoin[L, R any](left []LabeledTimeInterval[L], right []LabeledTimeInterval[R], joinType JoinType) []JoinPair[L, R] {
+	if joinType < InnerJoin || joinType > AntiJoin {
+		panic(fmt.Sprint("invalid join type: ", joinType))
+	}
+
+	checkSortedByStart("left", len(left), func(i int) *TimeInterval { return left[i].Interval })
+	checkSortedByStart("right", len(right), func(i int) *TimeInterval { return right[i].Interval })
+
+	// matches[i] are the indices of right overlapping left[i]
+	matches := make([][]int, len(left))
+
+	// the elements started but not ended yet at the current start
+	activeLeft := []int{}
+	activeRight := []int{}
+
+	// removes the elements ended by the start of ti, and calls f with the others overlapping ti.
+	// A zero duration ti overlaps the others started before it, and never overlaps
+	// the elements coming later, so it is not added to the active elements.
+	sweep := func(active []int, ti *TimeInterval, get func(int) *TimeInterval, f func(int)) []int {
+		ret := active[:0]
+		for _, v := range active {
+			if get(v).end.After(ti.start) {
+				ret = append(ret, v)
+
+				if !ti.IsZeroDuration() || get(v).start.Before(ti.start) {
+					f(v)
+				}
+			}
+		}
+		return ret
+	}
+
+	getLeft := func(i int) *TimeInterval { return left[i].Interval }
+	getRight := func(j int) *TimeInterval { return right[j].Interval }
+
+	// at the same start, zero duration first, and then left first
+	leftFirst := func(i, j int) bool {
+		a, b := left[i].Interval, right[j].Interval
+		if !a.start.Equal(b.start) {
+			return a.start.Before(b.start)
+		}
+		return a.IsZeroDuration() || !b.IsZeroDuration()
+	}
+
+	for i, j := 0, 0; i < len(left) || j < len(right); {
+		// the empty elements overlap nothing
+		if i < len(left) && left[i].Interval.IsEmpty() {
+			i++
+			continue
+		}
+		if j < len(right) && right[j].Interval.IsEmpty() {
+			j++
+			continue
+		}
+
+		if j == len(right) || i < len(left) && leftFirst(i, j) {
+			ti := left[i].Interval
+			activeRight = sweep(activeRight, ti, getRight, func(k int) {
+				matches[i] = append(matches[i], k)
+			})
+			if !ti.IsZeroDuration() {
+				activeLeft = append(activeLeft, i)
+			}
+			i++
+		} else {
+			ti := right[j].Interval
+			activeLeft = sweep(activeLeft, ti, getLeft, func(k int) {
+				matches[k] = append(matches[k], j)
+			})
+			if !ti.IsZeroDuration() {
+				activeRight = append(activeRight, j)
+			}
+			j++
+		}
+	}
+
+	ret := []JoinPair[L, R]{}
+
+	for i, v := range left {
+		if len(matches[i]) == 0 {
+			if joinType != InnerJoin {
+				ret = append(ret, JoinPair[L, R]{Left: v})
+			}
+			continue
+		}
+
+		if joinType == AntiJoin {
+			continue
+		}
+
+		for _, k := range matches[i] {
+			r := right[k]
+
+			ret = append(ret, JoinPair[L, R]{
+				Left:     v,
+				Right:    &r,
+				Interval: v.Interval.Intersection(r.Interval),
+			})
+		}
+	}
+
+	return ret
+}
+
+func checkSortedByStart(name string, n int, get func(i int) *TimeInterval) {
+	prev := -1
+	for i := 0; i < n; i++ {
+		if get(i).IsEmpty() {
+			continue
+		}
+
+		if prev >= 0 && get(i).start.Before(get(prev).start) {
+			panic(fmt.Sprintf("%s is not sorted by the start: %d, %d", name, prev, i))
+		}
+		prev = i
+	}
+}
@@ -0,0 +1,160 @@
+package timeinterval
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type TimeScale int
+
+const (
+	// TimeScalePOSIX is the time scale of NewTimePoint and Go's time package.
+	// Every day has exactly 86400 seconds and leap seconds are ignored.
+	TimeScalePOSIX TimeScale = iota
+	// TimeScaleUTC is UTC with leap seconds.
+	// A TimePoint can be at 23:59:60 of a day with a leap second,
+	// and Diff() and Duration() count the leap seconds in between (elapsed SI seconds).
+	TimeScaleUTC
+)
+
+// leapSeconds is the list of the midnights (in Unix seconds) right after the inserted leap seconds,
+// from IERS Bulletin C. No leap second is announced after 2016-12-31.
+var leapSeconds = []int64{
+	78796800,   // 1972-06-30
+	94694400,   // 1972-12-31
+	126230400,  // 1973-12-31
+	157766400,  // 1974-12-31
+	189302400,  // 1975-12-31
+	220924800,  // 1976-12-31
+	252460800,  // 1977-12-31
+	283996800,  // 1978-12-31
+	315532800,  // 1979-12-31
+	362793600,  // 1981-06-30
+	394329600,  // 1982-06-30
+	425865600,  // 1983-06-30
+	489024000,  // 1985-06-30
+	567993600,  // 1987-12-31
+	631152000,  // 1989-12-31
+	662688000,  // 1990-12-31
+	709948800,  // 1992-06-30
+	741484800,  // 1993-06-30
+	773020800,  // 1994-06-30
+	820454400,  // 1995-12-31
+	867715200,  // 1997-06-30
+	915148800,  // 1998-12-31
+	1136073600, // 2005-12-31
+	1230768000, // 2008-12-31
+	1341100800, // 2012-06-30
+	1435708800, // 2015-06-30
+	1483228800, // 2016-12-31
+}
+
+// taiMinusUTC1972 is TAI - UTC on 1972-01-01.
+// Before 1972, it is used as is (the fractional adjustments of the time are not supported).
+const taiMinusUTC1972 = 10 * time.Second
+
+// leapCount returns the number of leap seconds inserted before t
+func leapCount(t time.Time) int {
+	unix := t.Unix()
+
+	return sort.Search(len(leapSeconds), func(i int) bool { return leapSeconds[i] > unix })
+}
+
+// NewTimePointUTC is NewTimePoint in TimeScaleUTC.
+// sec can be 60 only at the end of a day with a leap second, e.g. 2016-12-31 23:59:60.
+// Otherwise, arguments are normalized as NewTimePoint does.
+func NewTimePointUTC(year, month, day, hour, minute, sec, nsec int) *TimePoint {
+	if sec == 60 && nsec >= 0 && nsec < int(time.Second) {
+		next := time.Date(year, time.Month(month), day, hour, minute, 60, 0, time.UTC)
+		if _, ok := sort.Find(len(leapSeconds), func(i int) int { return int(next.Unix() - leapSeconds[i]) }); ok {
+			prev := next.Add(-time.Second)
+
+			_year, _month, _day := prev.Date()
+
+			ret := &TimePoint{
+				year:       _year,
+				month:      int(_month),
+				day:        _day,
+				hour:       23,
+				minute:     59,
+				second:     60,
+				nanosecond: nsec,
+
+				// a leap second has the same t as the next midnight, see Compare()
+				t:     next,
+				scale: TimeScaleUTC,
+				leap:  true,
+			}
+
+			return ret
+		}
+	}
+
+	ret := NewTimePoint(year, month, day, hour, minute, sec, nsec)
+	ret.scale = TimeScaleUTC
+
+	return ret
+}
+
+// FromTAI returns the TimePoint in TimeScaleUTC of the TAI reading t.
+// The location of t is ignored.
+func FromTAI(t time.Time) *TimePoint {
+	// position on the timeline of NewTimePoint() if there were no leap seconds
+	x := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC).Add(-taiMinusUTC1972)
+
+	// leap second k (1-based) occupies [leapSeconds[k-1] + k - 1, leapSeconds[k-1] + k) of x
+	k := sort.Search(len(leapSeconds), func(i int) bool {
+		return x.Before(time.Unix(leapSeconds[i]+int64(i), 0))
+	})
+
+	if k > 0 && x.Before(time.Unix(leapSeconds[k-1]+int64(k), 0)) {
+		prev := time.Unix(leapSeconds[k-1]-1, 0).UTC()
+		nsec := x.Sub(time.Unix(leapSeconds[k-1]+int64(k)-1, 0))
+
+		return NewTimePointUTC(prev.Year(), int(prev.Month()), prev.Day(), 23, 59, 60, int(nsec))
+	}
+
+	ret := FromTime(x.Add(-time.Duration(k) * time.Second))
+	ret.scale = TimeScaleUTC
+
+	return ret
+}
+
+func (tp *TimePoint) Scale() TimeScale {
+	return tp.scale
+}
+
+func (tp *TimePoint) IsLeapSecond() bool {
+	return tp.leap
+}
+
+// TAI returns tp in International Atomic Time.
+// The location of the result is time.UTC, but the reading is of TAI.
+func (tp *TimePoint) TAI() time.Time {
+	return tp.tai().Add(taiMinusUTC1972)
+}
+
+// tai returns TAI - 10s, which is the same as t before 1972
+func (tp *TimePoint) tai() time.Time {
+	if tp.leap {
+		return tp.t.Add(time.Duration(leapCount(tp.t)-1)*time.Second + time.Duration(tp.nanosecond))
+	}
+
+	return tp.t.Add(time.Duration(leapCount(tp.t)) * time.Second)
+}
+
+// compareLeap compares TimePoints having the same t, at least one of which is a leap second
+func compareLeap(tp, tp2 *TimePoint) int {
+	switch {
+	case tp.leap && tp2.leap:
+		return cmp.Compare(tp.nanosecond, tp2.nanosecond)
+	case tp.leap:
+		return -1
+	case tp2.leap:
+		return +1
+	default:
+		panic(fmt.Sprint("not a leap second: ", tp, tp2))
+	}
+}
@@ -0,0 +1,668 @@
+package timeinterval
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrAmbiguous is wrapped by NaturalParseError when an expression has more than one reading,
+// e.g. "3" can be 03:00 or 15:00
+var ErrAmbiguous = errors.New("ambiguous")
+
+// NaturalParseError
+type NaturalParseError struct {
+	Input  string
+	Offset int // byte offset of the offending word in Input, len(Input) at the end
+	Err    error
+}
+
+func (e *NaturalParseError) Error() string {
+	return fmt.Sprintf("timeinterval: cannot parse %q at offset %d: %v", e.Input, e.Offset, e.Err)
+}
+
+func (e *NaturalParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseNatural resolves an English expression relative to ref in loc, e.g.
+//
+//	today, tomorrow, yesterday, 2024-06-03, Monday, this/next/last Monday
+//	this/next/last week, month, year or weekend
+//	last/next N days or weeks (whole days, today excluded)
+//	last/next N hours or minutes (from or to ref)
+//	Monday through Friday, next Monday to Friday
+//	any of the days above followed (or preceded) by a time of day:
+//	3pm, 14:30, noon, midnight, 2-4pm, 9 to 5, between 10am and noon,
+//	morning (06-12), afternoon (12-18), evening (18-22), night (22-06)
+//
+// A week starts on Monday. "next Monday" is the Monday of the next week,
+// and a bare weekday is the first one on or after today.
+// An hour from 1 to 12 without am or pm is ambiguous unless the other end of the range
+// has one or is in the 24-hour clock, taking the nearest one to that end (e.g. 12:30 to 1
+// is 12:30-13:00), or it reads as business hours (e.g. 9 to 5). Hours with minutes,
+// a leading zero, 0 or 13 to 23 are in the 24-hour clock.
+//
+// The days without a time of day make one TimeInterval,
+// and with a time of day, one TimeInterval per day.
+// A single time of day makes zero duration TimeIntervals.
+func ParseNatural(s string, ref *TimePoint, loc *time.Location) (*TimeIntervalSet, error) {
+	p := &naturalParser{
+		input:  s,
+		tokens: tokenizeNatural(s),
+		ref:    ref,
+		loc:    loc,
+	}
+
+	y, m, d := ref.t.In(loc).Date()
+	p.today = NewDate(y, int(m), d)
+
+	return p.parse()
+}
+
+// ParseNaturalInterval is ParseNatural for an expression of one TimeInterval
+func ParseNaturalInterval(s string, ref *TimePoint, loc *time.Location) (*TimeInterval, error) {
+	tis, err := ParseNatural(s, ref, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tis.elements) != 1 {
+		return nil, &NaturalParseError{Input: s, Err: fmt.Errorf("%d intervals instead of one", len(tis.elements))}
+	}
+
+	return tis.elements[0], nil
+}
+
+type naturalToken struct {
+	text   string
+	offset int
+}
+
+var naturalDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+
+// tokenizeNatural splits s into lowercase words, numbers (with colons), dates and symbols.
+// Dots in words (a.m.) and commas are dropped.
+func tokenizeNatural(s string) []naturalToken {
+	ret := []naturalToken{}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == ',':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			if m := naturalDatePattern.FindString(s[i:]); m != "" {
+				j += len(m)
+			} else {
+				for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == ':') {
+					j++
+				}
+			}
+			ret = append(ret, naturalToken{text: s[i:j], offset: i})
+			i = j
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(s) && (s[j] >= 'a' && s[j] <= 'z' || s[j] >= 'A' && s[j] <= 'Z' || s[j] == '.') {
+				j++
+			}
+			text := strings.ToLower(strings.ReplaceAll(s[i:j], ".", ""))
+			ret = append(ret, naturalToken{text: text, offset: i})
+			i = j
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			text := string(r)
+			if r == '–' || r == '—' {
+				text = "-"
+			}
+			ret = append(ret, naturalToken{text: text, offset: i})
+			i += size
+		}
+	}
+
+	return ret
+}
+
+type naturalParser struct {
+	input  string
+	tokens []naturalToken
+	pos    int
+
+	ref   *TimePoint
+	loc   *time.Location
+	today *Date
+}
+
+// naturalTimes is a time of day or a range of them
+type naturalTimes struct {
+	start *TimeOfDay
+	end   *TimeOfDay // nil for a single time of day
+}
+
+var naturalWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var naturalPeriods = map[string][2]int{
+	"morning":   {6, 12},
+	"afternoon": {12, 18},
+	"evening":   {18, 22},
+	"night":     {22, 6},
+}
+
+var naturalConnectors = []string{"-", "to", "through", "thru", "until", "till"}
+
+func (p *naturalParser) parse() (*TimeIntervalSet, error) {
+	if len(p.tokens) == 0 {
+		return nil, p.errorf("empty expression")
+	}
+
+	if ti, ok, err := p.parseRelativeTime(); ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expectEnd(); err != nil {
+			return nil, err
+		}
+
+		ret := NewTimeIntervalSet()
+		ret.Add(ti)
+
+		return ret, nil
+	}
+
+	var times *naturalTimes
+	var err error
+
+	// time of day first, e.g. 2-4pm tomorrow
+	if p.peekTimes() {
+		if times, err = p.parseTimes(); err != nil {
+			return nil, err
+		}
+	}
+
+	di := NewDateInterval(p.today, p.today)
+	if !p.atEnd() {
+		if di, err = p.parseDays(); err != nil {
+			return nil, err
+		}
+	}
+
+	if times == nil && p.peekTimes() {
+		if times, err = p.parseTimes(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case times == nil:
+		ret := NewTimeIntervalSet()
+		ret.Add(di.TimeInterval(p.loc))
+		return ret, nil
+	case times.end == nil:
+		ret := NewTimeIntervalSet()
+		for _, d := range di.Dates() {
+			tp := times.start.On(d, p.loc)
+			ret.Add(NewTimeInterval(tp, tp))
+		}
+		return ret, nil
+	default:
+		return NewTimeOfDayInterval(times.start, times.end).TimeIntervals(di, p.loc), nil
+	}
+}
+
+// parseRelativeTime parses last/next N hours or minutes
+func (p *naturalParser) parseRelativeTime() (*TimeInterval, bool, error) {
+	if p.pos+2 >= len(p.tokens) {
+		return nil, false, nil
+	}
+
+	var unit time.Duration
+	switch p.tokens[p.pos+2].text {
+	case "hour", "hours":
+		unit = time.Hour
+	case "minute", "minutes", "min", "mins":
+		unit = time.Minute
+	default:
+		return nil, false, nil
+	}
+
+	dir := p.tokens[p.pos].text
+	if dir != "last" && dir != "past" && dir != "next" {
+		return nil, false, nil
+	}
+
+	n, err := p.parseCount(p.pos + 1)
+	if err != nil {
+		return nil, true, err
+	}
+	p.pos += 3
+
+	d := unit * time.Duration(n)
+	if dir == "next" {
+		return NewTimeInterval(p.ref, FromTime(p.ref.t.Add(d))), true, nil
+	}
+
+	return NewTimeInterval(FromTime(p.ref.t.Add(-d)), p.ref), true, nil
+}
+
+// parseDays parses a day, a period of days or a range of them
+func (p *naturalParser) parseDays() (*DateInterval, error) {
+	first, err := p.parseDay(p.today)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos+1 >= len(p.tokens) || !p.isConnector(p.pos) || !p.isDayStart(p.pos+1) {
+		return first, nil
+	}
+	p.pos++
+
+	offset := p.peek().offset
+
+	// a bare weekday after a connector is the first one on or after the start
+	last, err := p.parseDay(first.first)
+	if err != nil {
+		return nil, err
+	}
+
+	if last.last.Before(first.first) {
+		return nil, p.errorAt(offset, "end is before start")
+	}
+
+	return NewDateInterval(first.first, last.last), nil
+}
+
+// parseDay parses a day or a period of days. A bare weekday is the first one on or after from.
+func (p *naturalParser) parseDay(from *Date) (*DateInterval, error) {
+	p.accept("on")
+
+	if p.atEnd() {
+		return nil, p.errorf("expected a day")
+	}
+
+	tok := p.next()
+	day := func(d *Date) (*DateInterval, error) {
+		return NewDateInterval(d, d), nil
+	}
+
+	switch tok.text {
+	case "today":
+		return day(p.today)
+	case "tomorrow":
+		return day(p.today.AddDays(1))
+	case "yesterday":
+		return day(p.today.AddDays(-1))
+	case "this", "next", "last", "past":
+		return p.parseRelativeDays(tok)
+	}
+
+	if wd, ok := naturalWeekdays[tok.text]; ok {
+		return day(from.AddDays((int(wd) - int(from.Weekday()) + 7) % 7))
+	}
+
+	if naturalDatePattern.MatchString(tok.text) {
+		t, err := time.Parse(time.DateOnly, tok.text)
+		if err != nil {
+			return nil, p.errorAt(tok.offset, "invalid date %q", tok.text)
+		}
+		return day(NewDate(t.Year(), int(t.Month()), t.Day()))
+	}
+
+	return nil, p.errorAt(tok.offset, "unexpected %q, expected a day", tok.text)
+}
+
+// parseRelativeDays parses the rest of this/next/last ..., where tok is this, next, last or past
+func (p *naturalParser) parseRelativeDays(tok naturalToken) (*DateInterval, error) {
+	if p.atEnd() {
+		return nil, p.errorf("expected a weekday, week, weekend, month, year or a number after %q", tok.text)
+	}
+
+	delta := map[string]int{"this": 0, "next": 1, "last": -1, "past": -1}[tok.text]
+
+	weekStart := p.today.AddDays(-((int(p.today.Weekday()) + 6) % 7))
+
+	unit := p.next()
+
+	if wd, ok := naturalWeekdays[unit.text]; ok && tok.text != "past" {
+		d := weekStart.AddDays(delta*7 + (int(wd)+6)%7)
+		return NewDateInterval(d, d), nil
+	}
+
+	switch unit.text {
+	case "week":
+		first := weekStart.AddDays(delta * 7)
+		return NewDateInterval(first, first.AddDays(6)), nil
+	case "weekend":
+		first := weekStart.AddDays(delta*7 + 5)
+		return NewDateInterval(first, first.AddDays(1)), nil
+	case "month":
+		first := NewDate(p.today.year, p.today.month+delta, 1)
+		return NewDateInterval(first, NewDate(first.year, first.month+1, 0)), nil
+	case "year":
+		first := NewDate(p.today.year+delta, 1, 1)
+		return NewDateInterval(first, NewDate(first.year, 12, 31)), nil
+	}
+
+	if tok.text == "this" || !isNumber(unit.text) {
+		return nil, p.errorAt(unit.offset, "unexpected %q after %q", unit.text, tok.text)
+	}
+
+	n, err := p.parseCount(p.pos - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.atEnd() {
+		return nil, p.errorf("expected days or weeks after %q", unit.text)
+	}
+
+	// whole days or weeks, excluding the current one
+	var first, last *Date
+	switch u := p.next(); u.text {
+	case "day", "days":
+		first, last = p.today.AddDays(1), p.today.AddDays(-1)
+	case "week", "weeks":
+		first, last = weekStart.AddDays(7), weekStart.AddDays(-1)
+		n *= 7
+	default:
+		return nil, p.errorAt(u.offset, "unexpected %q, expected days or weeks", u.text)
+	}
+
+	if delta > 0 {
+		return NewDateInterval(first, first.AddDays(n-1)), nil
+	}
+
+	return NewDateInterval(last.AddDays(-n+1), last), nil
+}
+
+// parseTimes parses a time of day, a range of them or a part of the day
+func (p *naturalParser) parseTimes() (*naturalTimes, error) {
+	between := false
+	switch {
+	case p.accept("at"), p.accept("from"):
+	case p.accept("between"):
+		between = true
+	}
+
+	if p.atEnd() {
+		return nil, p.errorf("expected a time of day")
+	}
+
+	if v, ok := naturalPeriods[p.peek().text]; ok && !between {
+		p.pos++
+		return &naturalTimes{start: NewTimeOfDay(v[0], 0, 0, 0), end: NewTimeOfDay(v[1], 0, 0, 0)}, nil
+	}
+
+	start, err := p.parseClock()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case between:
+		if !p.accept("and") {
+			return nil, p.errorf("expected \"and\"")
+		}
+	case !p.atEnd() && p.isConnector(p.pos):
+		p.pos++
+	default:
+		if start.meridiem == "" && !start.hour24 {
+			return nil, p.errorAt(start.offset, "%w: %d can be am or pm", ErrAmbiguous, start.hour)
+		}
+		return &naturalTimes{start: start.timeOfDay(start.meridiem)}, nil
+	}
+
+	end, err := p.parseClock()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.resolveClocks(start, end)
+}
+
+// naturalClock is a time of day as written
+type naturalClock struct {
+	hour     int
+	minute   int
+	meridiem string // "am", "pm" or ""
+	hour24   bool   // unambiguous without meridiem
+	offset   int
+}
+
+func (c *naturalClock) timeOfDay(meridiem string) *TimeOfDay {
+	hour := c.hour
+	switch meridiem {
+	case "am":
+		hour %= 12
+	case "pm":
+		hour = hour%12 + 12
+	}
+
+	return NewTimeOfDay(hour, c.minute, 0, 0)
+}
+
+func (p *naturalParser) parseClock() (*naturalClock, error) {
+	if p.atEnd() {
+		return nil, p.errorf("expected a time of day")
+	}
+
+	tok := p.next()
+
+	switch tok.text {
+	// as 12pm and 12am, so the other end without meridiem follows them, e.g. noon to 2 is 12:00-14:00
+	case "noon":
+		return &naturalClock{hour: 12, meridiem: "pm", offset: tok.offset}, nil
+	case "midnight":
+		return &naturalClock{hour: 0, meridiem: "am", offset: tok.offset}, nil
+	}
+
+	h, m, hasMinute := strings.Cut(tok.text, ":")
+	if !isNumber(h) || len(h) > 2 || hasMinute && (!isNumber(m) || len(m) != 2) {
+		return nil, p.errorAt(tok.offset, "unexpected %q, expected a time of day", tok.text)
+	}
+
+	ret := &naturalClock{offset: tok.offset}
+	ret.hour, _ = strconv.Atoi(h)
+	if hasMinute {
+		ret.minute, _ = strconv.Atoi(m)
+	}
+
+	if ret.hour > 23 || ret.minute > 59 {
+		return nil, p.errorAt(tok.offset, "invalid time of day %q", tok.text)
+	}
+
+	ret.hour24 = hasMinute || h[0] == '0' || ret.hour > 12
+
+	if p.accept("am") {
+		ret.meridiem = "am"
+	} else if p.accept("pm") {
+		ret.meridiem = "pm"
+	}
+
+	if ret.meridiem != "" && (ret.hour == 0 || ret.hour > 12) {
+		return nil, p.errorAt(tok.offset, "invalid time of day %q with %s", tok.text, ret.meridiem)
+	}
+
+	return ret, nil
+}
+
+// resolveClocks decides am or pm of the ends without one
+func (p *naturalParser) resolveClocks(start, end *naturalClock) (*naturalTimes, error) {
+	fixed := func(c *naturalClock) bool {
+		return c.meridiem != "" || c.hour24
+	}
+	// nearest returns c as am or pm, whichever makes the shorter interval by length
+	nearest := func(c *naturalClock, length func(tod *TimeOfDay) time.Duration) *TimeOfDay {
+		am, pm := c.timeOfDay("am"), c.timeOfDay("pm")
+		if length(pm) < length(am) {
+			return pm
+		}
+		return am
+	}
+
+	ret := &naturalTimes{}
+
+	switch {
+	case fixed(start) && fixed(end):
+		ret.start, ret.end = start.timeOfDay(start.meridiem), end.timeOfDay(end.meridiem)
+
+	case fixed(start):
+		// the first one after the start, e.g. 10am-2 is 10:00-14:00 and 12:30 to 1 is 12:30-13:00
+		ret.start = start.timeOfDay(start.meridiem)
+		ret.end = nearest(end, func(tod *TimeOfDay) time.Duration {
+			return NewTimeOfDayInterval(ret.start, tod).Duration()
+		})
+
+	case fixed(end):
+		// the last one before the end, e.g. 11-1pm is 11:00-13:00
+		ret.end = end.timeOfDay(end.meridiem)
+		ret.start = nearest(start, func(tod *TimeOfDay) time.Duration {
+			return NewTimeOfDayInterval(tod, ret.end).Duration()
+		})
+
+	case start.hour >= 7 && start.hour <= 11 && end.hour >= 1 && end.hour < start.hour:
+		// business hours, e.g. 9 to 5
+		ret.start, ret.end = start.timeOfDay("am"), end.timeOfDay("pm")
+
+	default:
+		return nil, p.errorAt(start.offset, "%w: %d and %d can be am or pm", ErrAmbiguous, start.hour, end.hour)
+	}
+
+	return ret, nil
+}
+
+func (p *naturalParser) parseCount(i int) (int, error) {
+	tok := p.tokens[i]
+
+	n, err := strconv.Atoi(tok.text)
+	if err != nil || n < 1 || n > 100000 {
+		return 0, p.errorAt(tok.offset, "invalid count %q", tok.text)
+	}
+
+	return n, nil
+}
+
+func (p *naturalParser) peekTimes() bool {
+	if p.atEnd() {
+		return false
+	}
+
+	text := p.peek().text
+	if _, ok := naturalPeriods[text]; ok {
+		return true
+	}
+
+	switch text {
+	case "at", "from", "between", "noon", "midnight":
+		return true
+	}
+
+	h, _, _ := strings.Cut(text, ":")
+	return isNumber(h)
+}
+
+func (p *naturalParser) isDayStart(i int) bool {
+	text := p.tokens[i].text
+	if _, ok := naturalWeekdays[text]; ok {
+		return true
+	}
+
+	switch text {
+	case "on", "today", "tomorrow", "yesterday", "this", "next", "last", "past":
+		return true
+	}
+
+	return naturalDatePattern.MatchString(text)
+}
+
+func (p *naturalParser) isConnector(i int) bool {
+	for _, v := range naturalConnectors {
+		if p.tokens[i].text == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *naturalParser) atEnd() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *naturalParser) peek() naturalToken {
+	return p.tokens[p.pos]
+}
+
+func (p *naturalParser) next() naturalToken {
+	ret := p.tokens[p.pos]
+	p.pos++
+
+	return ret
+}
+
+func (p *naturalParser) accept(text string) bool {
+	if !p.atEnd() && p.peek().text == text {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *naturalParser) expectEnd() error {
+	if p.atEnd() {
+		return nil
+	}
+
+	return p.errorAt(p.peek().offset, "unexpected %q", p.peek().text)
+}
+
+// errorf reports an error at the current token
+func (p *naturalParser) errorf(format string, a ...any) error {
+	offset := len(p.input)
+	if !p.atEnd() {
+		offset = p.peek().offset
+	}
+
+	return p.errorAt(offset, format, a...)
+}
+
+func (p *naturalParser) errorAt(offset int, format string, a ...any) error {
+	return &NaturalParseError{
+		Input:  p.input,
+		Offset: offset,
+		Err:    fmt.Errorf(format, a...),
+	}
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
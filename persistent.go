@@ -0,0 +1,257 @@
+package timeinterval
+
+import (
+	"time"
+)
+
+// PersistentTimeIntervalSet
+//
+// Immutable counterpart of TimeIntervalSet backed by a persistent AVL tree.
+// Every operation returns a new version sharing unchanged subtrees with
+// the old one, so keeping old versions (snapshots, undo) is cheap.
+// Elements are always kept sorted in the order of TimeIntervalSet.Sort().
+type PersistentTimeIntervalSet struct {
+	root *ptisNode
+}
+
+type ptisNode struct {
+	ti *TimeInterval
+
+	left  *ptisNode
+	right *ptisNode
+
+	height   int
+	size     int
+	duration time.Duration
+}
+
+func NewPersistentTimeIntervalSet(ti ...*TimeInterval) *PersistentTimeIntervalSet {
+	ret := &PersistentTimeIntervalSet{}
+
+	return ret.Add(ti...)
+}
+
+func (ptis *PersistentTimeIntervalSet) Len() int {
+	return ptis.root.getSize()
+}
+
+func (ptis *PersistentTimeIntervalSet) Duration() time.Duration {
+	return ptis.root.getDuration()
+}
+
+// Elements returns the elements in sorted order
+func (ptis *PersistentTimeIntervalSet) Elements() []*TimeInterval {
+	ret := make([]*TimeInterval, 0, ptis.Len())
+
+	var walk func(n *ptisNode)
+	walk = func(n *ptisNode) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		ret = append(ret, n.ti)
+		walk(n.right)
+	}
+	walk(ptis.root)
+
+	return ret
+}
+
+func (ptis *PersistentTimeIntervalSet) TimeIntervalSet() *TimeIntervalSet {
+	ret := NewTimeIntervalSet()
+
+	ret.Add(ptis.Elements()...)
+
+	return ret
+}
+
+func (ptis *PersistentTimeIntervalSet) Copy() *PersistentTimeIntervalSet {
+	// PersistentTimeIntervalSet is immutable
+	return ptis
+}
+
+func (ptis *PersistentTimeIntervalSet) Clear() *PersistentTimeIntervalSet {
+	return &PersistentTimeIntervalSet{}
+}
+
+func (ptis *PersistentTimeIntervalSet) Contains(ti *TimeInterval) bool {
+	n := ptis.root
+	for n != nil {
+		c := compareTimeInterval(ti, n.ti)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ptis *PersistentTimeIntervalSet) Add(ti ...*TimeInterval) *PersistentTimeIntervalSet {
+	root := ptis.root
+	for _, v := range ti {
+		if v == nil {
+			panic("nil argument")
+		}
+		root = root.insert(v)
+	}
+
+	return &PersistentTimeIntervalSet{root: root}
+}
+
+// Remove returns a new version without one element equal to ti.
+// If there is no such element, ptis itself is returned.
+func (ptis *PersistentTimeIntervalSet) Remove(ti *TimeInterval) *PersistentTimeIntervalSet {
+	root, ok := ptis.root.remove(ti)
+	if !ok {
+		return ptis
+	}
+
+	return &PersistentTimeIntervalSet{root: root}
+}
+
+func (ptis *PersistentTimeIntervalSet) Merge(ptis2 ...*PersistentTimeIntervalSet) *PersistentTimeIntervalSet {
+	ret := ptis
+	for _, v := range ptis2 {
+		ret = ret.Add(v.Elements()...)
+	}
+
+	return ret
+}
+
+// Cleanup returns a new version cleaned up as TimeIntervalSet.Cleanup() does
+func (ptis *PersistentTimeIntervalSet) Cleanup(removeZeroDuration bool) *PersistentTimeIntervalSet {
+	tis := ptis.TimeIntervalSet()
+	tis.Cleanup(removeZeroDuration)
+
+	return &PersistentTimeIntervalSet{root: buildPtisNode(tis.elements)}
+}
+
+// buildPtisNode builds a balanced tree from sorted elements
+func buildPtisNode(elements []*TimeInterval) *ptisNode {
+	if len(elements) == 0 {
+		return nil
+	}
+
+	m := len(elements) / 2
+
+	return newPtisNode(elements[m], buildPtisNode(elements[:m]), buildPtisNode(elements[m+1:]))
+}
+
+func newPtisNode(ti *TimeInterval, left, right *ptisNode) *ptisNode {
+	ret := &ptisNode{
+		ti: ti,
+
+		left:  left,
+		right: right,
+
+		height:   max(left.getHeight(), right.getHeight()) + 1,
+		size:     left.getSize() + right.getSize() + 1,
+		duration: addSaturated(addSaturated(left.getDuration(), right.getDuration()), ti.Duration()),
+	}
+
+	return ret
+}
+
+func (n *ptisNode) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *ptisNode) getSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *ptisNode) getDuration() time.Duration {
+	if n == nil {
+		return time.Duration(0)
+	}
+	return n.duration
+}
+
+// insert returns a new tree with ti, copying only the nodes on the path
+func (n *ptisNode) insert(ti *TimeInterval) *ptisNode {
+	if n == nil {
+		return newPtisNode(ti, nil, nil)
+	}
+
+	if compareTimeInterval(ti, n.ti) < 0 {
+		return balancePtisNode(n.ti, n.left.insert(ti), n.right)
+	}
+
+	return balancePtisNode(n.ti, n.left, n.right.insert(ti))
+}
+
+func (n *ptisNode) remove(ti *TimeInterval) (*ptisNode, bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	c := compareTimeInterval(ti, n.ti)
+	switch {
+	case c < 0:
+		left, ok := n.left.remove(ti)
+		if !ok {
+			return n, false
+		}
+		return balancePtisNode(n.ti, left, n.right), true
+	case c > 0:
+		right, ok := n.right.remove(ti)
+		if !ok {
+			return n, false
+		}
+		return balancePtisNode(n.ti, n.left, right), true
+	}
+
+	if n.left == nil {
+		return n.right, true
+	}
+	if n.right == nil {
+		return n.left, true
+	}
+
+	// replace with the smallest element of the right subtree
+	m := n.right
+	for m.left != nil {
+		m = m.left
+	}
+	right, _ := n.right.remove(m.ti)
+
+	return balancePtisNode(m.ti, n.left, right), true
+}
+
+func balancePtisNode(ti *TimeInterval, left, right *ptisNode) *ptisNode {
+	lh := left.getHeight()
+	rh := right.getHeight()
+
+	if lh > rh+1 {
+		if left.left.getHeight() >= left.right.getHeight() {
+			// single right rotation
+			return newPtisNode(left.ti, left.left, newPtisNode(ti, left.right, right))
+		}
+		// double rotation
+		lr := left.right
+		return newPtisNode(lr.ti, newPtisNode(left.ti, left.left, lr.left), newPtisNode(ti, lr.right, right))
+	}
+
+	if rh > lh+1 {
+		if right.right.getHeight() >= right.left.getHeight() {
+			// single left rotation
+			return newPtisNode(right.ti, newPtisNode(ti, left, right.left), right.right)
+		}
+		// double rotation
+		rl := right.left
+		return newPtisNode(rl.ti, newPtisNode(ti, left, rl.left), newPtisNode(right.ti, rl.right, right.right))
+	}
+
+	return newPtisNode(ti, left, right)
+}
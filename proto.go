@@ -0,0 +1,74 @@
+package timeinterval
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProtoTimestamp is satisfied by *timestamppb.Timestamp
+// (google.golang.org/protobuf/types/known/timestamppb),
+// so this package does not depend on protobuf.
+type ProtoTimestamp interface {
+	GetSeconds() int64
+	GetNanos() int32
+}
+
+// ProtoDuration is satisfied by *durationpb.Duration
+// (google.golang.org/protobuf/types/known/durationpb)
+type ProtoDuration interface {
+	GetSeconds() int64
+	GetNanos() int32
+}
+
+// FromProtoTimestamp panics if ts is nil or its nanos is out of [0, 999999999]
+func FromProtoTimestamp(ts ProtoTimestamp) *TimePoint {
+	if ts == nil {
+		panic("nil argument")
+	}
+
+	nanos := ts.GetNanos()
+	if nanos < 0 || nanos > 999999999 {
+		panic(fmt.Sprint("invalid nanos of timestamp: ", nanos))
+	}
+
+	return FromUnix(ts.GetSeconds(), int64(nanos))
+}
+
+// ProtoTimestamp returns the fields of google.protobuf.Timestamp, e.g.
+//
+//	seconds, nanos := tp.ProtoTimestamp()
+//	ts := &timestamppb.Timestamp{Seconds: seconds, Nanos: nanos}
+func (tp *TimePoint) ProtoTimestamp() (seconds int64, nanos int32) {
+	return tp.t.Unix(), int32(tp.t.Nanosecond())
+}
+
+// FromProtoDuration panics if d is nil or its nanos is out of [-999999999, 999999999]
+// or has a different sign from its seconds
+func FromProtoDuration(d ProtoDuration) WideDuration {
+	if d == nil {
+		panic("nil argument")
+	}
+
+	seconds, nanos := d.GetSeconds(), d.GetNanos()
+	if nanos < -999999999 || nanos > 999999999 || (seconds < 0 && nanos > 0) || (seconds > 0 && nanos < 0) {
+		panic(fmt.Sprint("invalid duration: ", seconds, nanos))
+	}
+
+	return NewWideDuration(seconds, int64(nanos))
+}
+
+// ProtoDuration returns the fields of google.protobuf.Duration, e.g.
+//
+//	seconds, nanos := wd.ProtoDuration()
+//	d := &durationpb.Duration{Seconds: seconds, Nanos: nanos}
+func (wd WideDuration) ProtoDuration() (seconds int64, nanos int32) {
+	seconds, ns := wd.seconds, wd.nanoseconds
+
+	// nanos of google.protobuf.Duration has the same sign as seconds
+	if seconds < 0 && ns > 0 {
+		seconds++
+		ns -= int64(time.Second)
+	}
+
+	return seconds, int32(ns)
+}
@@ -0,0 +1,223 @@
+package timeinterval
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SchedulerHooks are called with the TimeInterval starting or ending. Both can be nil.
+type SchedulerHooks struct {
+	OnStart func(ti *TimeInterval)
+	OnEnd   func(ti *TimeInterval)
+}
+
+// Scheduler
+//
+// Calls SchedulerHooks when TimeIntervals start and end by a Clock.
+// Hooks are called one at a time by Run() in the order of time.
+// At the same time, ends are called before starts, as TimeIntervals touching each other
+// do not overlap, except the end of a zero duration TimeInterval which follows its start.
+type Scheduler struct {
+	mu sync.Mutex
+
+	clock  Clock
+	events []*schedulerEvent // sorted
+	seq    uint64
+
+	wake chan struct{}
+}
+
+type schedulerEntry struct {
+	remaining int
+	stop      func() bool
+}
+
+type schedulerEvent struct {
+	tp    *TimePoint
+	order int // 0: end, 1: start, 2: end of zero duration
+	seq   uint64
+
+	ti    *TimeInterval
+	hook  func(ti *TimeInterval)
+	entry *schedulerEntry
+}
+
+func NewScheduler(clock Clock) *Scheduler {
+	if clock == nil {
+		panic("nil argument")
+	}
+
+	ret := &Scheduler{
+		clock:  clock,
+		events: []*schedulerEvent{},
+		wake:   make(chan struct{}, 1),
+	}
+
+	return ret
+}
+
+// Add schedules hooks for ti. ti which is empty or has already ended by the clock is ignored,
+// and OnStart of ti which has already started is called right away.
+// Cancelling ctx removes the hooks not called yet, e.g. OnEnd after OnStart.
+func (s *Scheduler) Add(ctx context.Context, ti *TimeInterval, hooks SchedulerHooks) {
+	tis := NewTimeIntervalSet()
+	tis.Add(ti)
+
+	s.AddSet(ctx, tis, hooks)
+}
+
+// AddSet is Add for each element of tis, e.g. expanded by CronSchedule.TimeIntervals()
+func (s *Scheduler) AddSet(ctx context.Context, tis *TimeIntervalSet, hooks SchedulerHooks) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := FromTime(s.clock.Now())
+
+	entry := &schedulerEntry{}
+
+	for _, ti := range tis.elements {
+		if ti.IsEmpty() || ti.end.Before(now) {
+			continue
+		}
+
+		endOrder := 0
+		if ti.IsZeroDuration() {
+			endOrder = 2
+		}
+
+		s.insert(&schedulerEvent{tp: ti.start, order: 1, ti: ti, hook: hooks.OnStart, entry: entry})
+		s.insert(&schedulerEvent{tp: ti.end, order: endOrder, ti: ti, hook: hooks.OnEnd, entry: entry})
+		entry.remaining += 2
+	}
+
+	if entry.remaining == 0 {
+		return
+	}
+
+	entry.stop = context.AfterFunc(ctx, func() {
+		s.remove(entry)
+	})
+
+	s.signal()
+}
+
+// Pending returns the number of the hooks not called yet
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.events)
+}
+
+// Run calls the hooks until ctx is done, and returns ctx.Err().
+// Only one Run() can be active at a time.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		due, next, ok := s.popDue()
+
+		for _, ev := range due {
+			if ev.hook != nil {
+				ev.hook(ev.ti)
+			}
+		}
+
+		if len(due) > 0 {
+			continue
+		}
+
+		var timer Timer
+		var timerC <-chan time.Time
+		if ok {
+			timer = s.clock.NewTimer(next)
+			timerC = timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return ctx.Err()
+		case <-timerC:
+		case <-s.wake:
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+	}
+}
+
+// popDue removes the events due and returns them,
+// with the duration until the next event if any
+func (s *Scheduler) popDue() ([]*schedulerEvent, time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+
+	i := 0
+	for ; i < len(s.events) && !s.events[i].tp.t.After(now); i++ {
+		entry := s.events[i].entry
+
+		entry.remaining--
+		if entry.remaining == 0 {
+			entry.stop()
+		}
+	}
+
+	due := slices.Clone(s.events[:i])
+	s.events = slices.Delete(s.events, 0, i)
+
+	if len(s.events) == 0 {
+		return due, 0, false
+	}
+
+	return due, s.events[0].tp.t.Sub(now), true
+}
+
+func (s *Scheduler) insert(ev *schedulerEvent) {
+	s.seq++
+	ev.seq = s.seq
+
+	i, _ := slices.BinarySearchFunc(s.events, ev, compareSchedulerEvent)
+	s.events = slices.Insert(s.events, i, ev)
+}
+
+func (s *Scheduler) remove(entry *schedulerEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = slices.DeleteFunc(s.events, func(ev *schedulerEvent) bool {
+		return ev.entry == entry
+	})
+	entry.remaining = 0
+
+	s.signal()
+}
+
+// signal wakes Run() up to see the changed events
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func compareSchedulerEvent(a, b *schedulerEvent) int {
+	if v := a.tp.t.Compare(b.tp.t); v != 0 {
+		return v
+	}
+
+	if a.order != b.order {
+		return a.order - b.order
+	}
+
+	return cmp.Compare(a.seq, b.seq)
+}
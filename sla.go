@@ -0,0 +1,129 @@
+package timeinterval
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// SLA
+//
+// Availability target measured over Window. Maintenance and Calendar are optional (nil).
+// Only the parts of Window within Calendar, e.g. business hours by TimeOfDayInterval.TimeIntervals(),
+// and not within Maintenance are measured, so outages elsewhere do not count.
+type SLA struct {
+	Window *TimeInterval
+
+	// Target is the availability required in percent, e.g. 99.9
+	Target float64
+
+	// Maintenance is the planned maintenance excluded from the measurement
+	Maintenance *TimeIntervalSet
+
+	// Calendar is the time measured, nil for all the time
+	Calendar *TimeIntervalSet
+}
+
+// SLAReport
+type SLAReport struct {
+	Window *TimeInterval
+
+	// Measured is the duration of Window measured by SLA
+	Measured time.Duration
+
+	// Downtime is the duration of the outages within Measured, overlapping outages counted once
+	Downtime time.Duration
+
+	// Availability is (Measured - Downtime) / Measured in percent, 100 if Measured is 0
+	Availability float64
+
+	// Budget is the downtime allowed by SLA.Target
+	Budget time.Duration
+
+	// BudgetConsumed is Downtime / Budget in percent, which can be over 100,
+	// and is +Inf for downtime without budget
+	BudgetConsumed float64
+
+	// BudgetRemaining is Budget - Downtime, negative if the budget is exceeded
+	BudgetRemaining time.Duration
+
+	// Met is true if Downtime is within Budget
+	Met bool
+}
+
+// Report returns SLAReport of outages over Window
+func (s *SLA) Report(outages *TimeIntervalSet) *SLAReport {
+	s.validate()
+
+	return s.report(s.Window, outages)
+}
+
+// Breakdown returns SLAReport of outages for each element of periods,
+// e.g. the days of DateInterval.Dates(), clipped to Window.
+// The budget of each period is of its own Measured.
+func (s *SLA) Breakdown(outages *TimeIntervalSet, periods *TimeIntervalSet) []*SLAReport {
+	s.validate()
+
+	ret := make([]*SLAReport, len(periods.elements))
+
+	for i, v := range periods.elements {
+		ret[i] = s.report(v.Intersection(s.Window), outages)
+	}
+
+	return ret
+}
+
+func (s *SLA) validate() {
+	if s.Window == nil {
+		panic("nil Window")
+	}
+
+	if s.Target < 0 || s.Target > 100 {
+		panic(fmt.Sprint("invalid target: ", s.Target))
+	}
+}
+
+// measured returns the parts of window measured
+func (s *SLA) measured(window *TimeInterval) *TimeIntervalSet {
+	ret := NewTimeIntervalSet()
+	ret.Add(window)
+
+	if s.Calendar != nil {
+		ret = ret.Intersect(s.Calendar)
+	}
+
+	if s.Maintenance != nil {
+		ret = ret.Subtract(s.Maintenance)
+	}
+
+	return ret
+}
+
+func (s *SLA) report(window *TimeInterval, outages *TimeIntervalSet) *SLAReport {
+	measured := s.measured(window)
+
+	ret := &SLAReport{
+		Window:       window,
+		Measured:     measured.Duration(),
+		Downtime:     outages.Intersect(measured).Duration(),
+		Availability: 100,
+	}
+
+	if ret.Measured > 0 {
+		ret.Availability = float64(ret.Measured-ret.Downtime) / float64(ret.Measured) * 100
+	}
+
+	ret.Budget = time.Duration(float64(ret.Measured) * (100 - s.Target) / 100)
+	ret.BudgetRemaining = ret.Budget - ret.Downtime
+
+	switch {
+	case ret.Budget > 0:
+		ret.BudgetConsumed = float64(ret.Downtime) / float64(ret.Budget) * 100
+	case ret.Downtime > 0:
+		ret.BudgetConsumed = math.Inf(1)
+	}
+
+	ret.Met = ret.Downtime <= ret.Budget
+
+	return ret
+}
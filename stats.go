@@ -0,0 +1,135 @@
+package timeinterval
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Stats
+//
+// Summary of a TimeIntervalSet computed by TimeIntervalSet.Stats().
+// The duration statistics are of the elements as they are, before Cleanup(),
+// so overlapping elements are counted separately, e.g. each incident of a set of incidents.
+// Covered, Coverage and LongestRun are of the elements after Cleanup(true),
+// so overlapping elements are counted once.
+// The empty elements are ignored.
+type Stats struct {
+	Count int
+	Total time.Duration // saturates as Duration() does
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+
+	Median time.Duration
+
+	// Covered is the duration of the parts of the bounds covered by the elements
+	Covered time.Duration
+	// Coverage is Covered / the duration of the bounds, 0 for zero duration bounds
+	Coverage float64
+
+	// LongestRun is the longest element after Cleanup(true), empty if there are none
+	LongestRun *TimeInterval
+
+	// Histogram[i] is the number of durations in [bins[i-1], bins[i]),
+	// where bins[-1] is 0 and bins[len(bins)] is infinite
+	Histogram []int
+
+	durations []time.Duration // sorted
+}
+
+// Stats returns Stats of tis, with Coverage against bounds
+// and Histogram by bins, which are the upper ends of the bins in ascending order.
+// tis is not modified.
+//
+// Count, Total, Min, Max, Mean and Histogram are computed in a single pass over the elements.
+// Two more passes are needed, each O(n log n): the durations are sorted for the exact
+// Median and Percentile(), and a copy of tis is cleaned up for Covered, Coverage and LongestRun,
+// as overlapping elements can be merged only in the order of the starts.
+func (tis *TimeIntervalSet) Stats(bounds *TimeInterval, bins []time.Duration) *Stats {
+	if bounds == nil {
+		panic("nil argument")
+	}
+	for i := 1; i < len(bins); i++ {
+		if bins[i] <= bins[i-1] {
+			panic(fmt.Sprint("bins are not in ascending order: ", bins))
+		}
+	}
+
+	ret := &Stats{
+		LongestRun: NewEmptyTimeInterval(),
+		Histogram:  make([]int, len(bins)+1),
+		durations:  make([]time.Duration, 0, len(tis.elements)),
+	}
+
+	for _, v := range tis.elements {
+		if v.IsEmpty() {
+			continue
+		}
+
+		d := v.Duration()
+
+		if ret.Count == 0 || d < ret.Min {
+			ret.Min = d
+		}
+		if ret.Count == 0 || d > ret.Max {
+			ret.Max = d
+		}
+		ret.Count++
+		ret.Total = addSaturated(ret.Total, d)
+		ret.durations = append(ret.durations, d)
+
+		i, _ := slices.BinarySearch(bins, d)
+		if i < len(bins) && bins[i] == d {
+			i++
+		}
+		ret.Histogram[i]++
+	}
+
+	if ret.Count == 0 {
+		return ret
+	}
+
+	ret.Mean = ret.Total / time.Duration(ret.Count)
+
+	slices.Sort(ret.durations)
+	ret.Median = ret.Percentile(50)
+
+	for _, v := range tis.cleanedUp().elements {
+		ret.Covered = addSaturated(ret.Covered, v.OverlapDuration(bounds))
+
+		if ret.LongestRun.IsEmpty() || v.Duration() > ret.LongestRun.Duration() {
+			ret.LongestRun = v
+		}
+	}
+
+	if bounds.Duration() > 0 {
+		ret.Coverage = float64(ret.Covered) / float64(bounds.Duration())
+	}
+
+	return ret
+}
+
+// Percentile returns the p-th percentile of the durations, in [0, 100],
+// interpolated linearly between the closest ranks. It is 0 if there are no durations.
+func (s *Stats) Percentile(p float64) time.Duration {
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		panic(fmt.Sprint("invalid percentile: ", p))
+	}
+
+	if len(s.durations) == 0 {
+		return time.Duration(0)
+	}
+
+	rank := p / 100 * float64(len(s.durations)-1)
+	i := int(rank)
+
+	if i == len(s.durations)-1 {
+		return s.durations[i]
+	}
+
+	lo, hi := s.durations[i], s.durations[i+1]
+
+	return lo + time.Duration(float64(hi-lo)*(rank-float64(i)))
+}
@@ -0,0 +1,100 @@
+package timeinterval_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestResolveOverlaps(t *testing.T) {
+	at := func(hour int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, day, hour, 0, 0, 0)
+	}
+	value := func(h1, h2 int, v float64) timeinterval.LabeledTimeInterval[float64] {
+		return timeinterval.LabeledTimeInterval[float64]{Interval: timeinterval.NewTimeInterval(at(h1), at(h2)), Label: v}
+	}
+
+	values := []timeinterval.LabeledTimeInterval[float64]{
+		value(0, 4, 2),
+		value(2, 6, 1),
+		value(6, 8, 3),
+		value(10, 12, 5),
+		value(11, 11, 100), // zero duration
+	}
+	window := timeinterval.NewTimeInterval(at(1), at(11))
+
+	assert.Equal(t, timeinterval.ResolveOverlaps(values, window, timeinterval.OverlapSum), []timeinterval.LabeledTimeInterval[float64]{
+		value(1, 2, 2),
+		value(2, 4, 3),
+		value(4, 6, 1),
+		value(6, 8, 3),
+		value(10, 11, 5),
+	})
+
+	// merged
+	assert.Equal(t, timeinterval.ResolveOverlaps(values, window, timeinterval.OverlapMax), []timeinterval.LabeledTimeInterval[float64]{
+		value(1, 4, 2),
+		value(4, 6, 1),
+		value(6, 8, 3),
+		value(10, 11, 5),
+	})
+
+	assert.Equal(t, timeinterval.ResolveOverlaps(values, window, timeinterval.OverlapMin)[:2], []timeinterval.LabeledTimeInterval[float64]{
+		value(1, 2, 2),
+		value(2, 6, 1),
+	})
+
+	assert.Equal(t, timeinterval.ResolveOverlaps(values, window, timeinterval.OverlapLast)[:2], []timeinterval.LabeledTimeInterval[float64]{
+		value(1, 2, 2),
+		value(2, 6, 1),
+	})
+
+	assert.Equal(t, timeinterval.ResolveOverlaps([]timeinterval.LabeledTimeInterval[float64]{value(2, 6, 1), value(0, 4, 2)}, window, timeinterval.OverlapLast)[:2], []timeinterval.LabeledTimeInterval[float64]{
+		value(1, 4, 2),
+		value(4, 6, 1),
+	})
+
+	assert.Equal(t, len(timeinterval.ResolveOverlaps(values, timeinterval.NewTimeInterval(at(8), at(10)), timeinterval.OverlapSum)), 0)
+
+	assert.Panics(t, func() {
+		timeinterval.ResolveOverlaps(values, window, timeinterval.OverlapPolicy(-1))
+	})
+}
+
+func TestTimeWeighted(t *testing.T) {
+	at := func(hour int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, day, hour, 0, 0, 0)
+	}
+	value := func(h1, h2 int, v float64) timeinterval.LabeledTimeInterval[float64] {
+		return timeinterval.LabeledTimeInterval[float64]{Interval: timeinterval.NewTimeInterval(at(h1), at(h2)), Label: v}
+	}
+
+	// CPU limits of pods
+	values := []timeinterval.LabeledTimeInterval[float64]{
+		value(0, 4, 2),
+		value(2, 6, 1),
+		value(10, 12, 0.5),
+	}
+
+	agg := timeinterval.TimeWeighted(values, timeinterval.NewTimeInterval(at(0), at(12)), timeinterval.OverlapSum)
+	assert.Equal(t, agg.Covered, time.Hour*8)
+	assert.InDelta(t, agg.Integral, (2*2+3*2+1*2+0.5*2)*3600, 1e-6)
+	assert.InDelta(t, agg.Average, 13.0/8, 1e-9)
+	assert.Equal(t, agg.Min, 0.5)
+	assert.Equal(t, agg.Max, 3.0)
+
+	// clipped
+	agg = timeinterval.TimeWeighted(values, timeinterval.NewTimeInterval(at(3), at(5)), timeinterval.OverlapSum)
+	assert.Equal(t, agg.Covered, time.Hour*2)
+	assert.InDelta(t, agg.Average, 2.0, 1e-9)
+
+	agg = timeinterval.TimeWeighted(values, timeinterval.NewTimeInterval(at(7), at(9)), timeinterval.OverlapSum)
+	assert.Equal(t, agg.Covered, time.Duration(0))
+	assert.Equal(t, agg.Integral, 0.0)
+	assert.Equal(t, math.IsNaN(agg.Average), true)
+	assert.Equal(t, math.IsNaN(agg.Min), true)
+}
@@ -0,0 +1,303 @@
+package timeinterval_test
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/iloy/timeinterval"
+)
+
+var benchSizes = []int{10, 100, 1000, 10000}
+
+// benchSet returns n TimeIntervals of up to an hour starting in a year, in random order
+func benchSet(n int, seed int64) *timeinterval.TimeIntervalSet {
+	rnd := rand.New(rand.NewSource(seed))
+
+	base := timeinterval.NewTimePoint(year, 1, 1, 0, 0, 0, 0).ToTime()
+
+	ret := timeinterval.NewTimeIntervalSet()
+	for i := 0; i < n; i++ {
+		start := base.Add(time.Duration(rnd.Int63n(int64(time.Hour * 24 * 365))))
+		ret.Add(timeinterval.NewTimeInterval(
+			timeinterval.FromTime(start),
+			timeinterval.FromTime(start.Add(time.Duration(rnd.Int63n(int64(time.Hour))))),
+		))
+	}
+
+	return ret
+}
+
+func benchSizesRun(b *testing.B, f func(b *testing.B, n int)) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			f(b, n)
+		})
+	}
+}
+
+func BenchmarkNewTimePoint(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		timeinterval.NewTimePoint(year, month, day, 19, 0, 0, i)
+	}
+}
+
+func BenchmarkTimePointCompare(b *testing.B) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t1.Compare(t2)
+	}
+}
+
+func BenchmarkNewTimeInterval(b *testing.B) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		timeinterval.NewTimeInterval(t1, t2)
+	}
+}
+
+func BenchmarkTimeIntervalIntersects(b *testing.B) {
+	elements := benchSet(2, 1).Elements()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		elements[0].Intersects(elements[1])
+	}
+}
+
+func BenchmarkTimeIntervalIntersection(b *testing.B) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	ti := timeinterval.NewTimeInterval(t1, t2)
+	ti2 := ti.Shift(time.Minute)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ti.Intersection(ti2)
+	}
+}
+
+func BenchmarkTimeIntervalSubtract(b *testing.B) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+	ti := timeinterval.NewTimeInterval(t1, t2)
+	ti2 := ti.Shrink(time.Minute, time.Minute)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ti.Subtract(ti2)
+	}
+}
+
+func BenchmarkTimeIntervalString(b *testing.B) {
+	ti := benchSet(1, 1).Elements()[0]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ti.String()
+	}
+}
+
+func BenchmarkTimeIntervalSetSort(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		tis := benchSet(n, 1)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			tis2 := tis.Copy()
+			b.StartTimer()
+
+			tis2.Sort()
+		}
+	})
+}
+
+func BenchmarkTimeIntervalSetCleanup(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		tis := benchSet(n, 1)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			tis2 := tis.Copy()
+			b.StartTimer()
+
+			tis2.Cleanup(true)
+		}
+	})
+}
+
+func BenchmarkTimeIntervalSetUnion(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		tis, tis2 := benchSet(n, 1), benchSet(n, 2)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tis.Union(tis2)
+		}
+	})
+}
+
+func BenchmarkTimeIntervalSetIntersect(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		tis, tis2 := benchSet(n, 1), benchSet(n, 2)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tis.Intersect(tis2)
+		}
+	})
+}
+
+func BenchmarkTimeIntervalSetSubtract(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		tis, tis2 := benchSet(n, 1), benchSet(n, 2)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tis.Subtract(tis2)
+		}
+	})
+}
+
+func BenchmarkTimeIntervalSetGaps(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		tis := benchSet(n, 1)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tis.Gaps()
+		}
+	})
+}
+
+func BenchmarkTimeIntervalSetCoalesce(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		tis := benchSet(n, 1)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			tis2 := tis.Copy()
+			b.StartTimer()
+
+			tis2.Coalesce(time.Hour)
+		}
+	})
+}
+
+func BenchmarkTimeIntervalSetStats(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		tis := benchSet(n, 1)
+		bounds := tis.Span()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tis.Stats(bounds, []time.Duration{time.Minute, time.Minute * 10})
+		}
+	})
+}
+
+func BenchmarkCompactSort(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		s := benchSet(n, 1).Compact()
+		buf := make([]timeinterval.CompactTimeInterval, len(s))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			copy(buf, s)
+			timeinterval.SortCompact(buf)
+		}
+	})
+}
+
+func BenchmarkCompactCleanup(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		s := benchSet(n, 1).Compact()
+		buf := make([]timeinterval.CompactTimeInterval, len(s))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			copy(buf, s)
+			timeinterval.CleanupCompact(buf, true)
+		}
+	})
+}
+
+func BenchmarkColumnarTimeIntervalSetCleanup(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		ctis := benchSet(n, 1).Columnar()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			ctis2 := ctis.Copy()
+			b.StartTimer()
+
+			ctis2.Cleanup(true)
+		}
+	})
+}
+
+func BenchmarkColumnarTimeIntervalSetSubtract(b *testing.B) {
+	benchSizesRun(b, func(b *testing.B, n int) {
+		ctis, ctis2 := benchSet(n, 1).Columnar(), benchSet(n, 2).Columnar()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ctis.Subtract(ctis2)
+		}
+	})
+}
+
+// BenchmarkMemoryFootprint reports the heap live per element of the sets built
+func BenchmarkMemoryFootprint(b *testing.B) {
+	const n = 100000
+
+	s := benchSet(1, 1).Compact()
+	for i := 1; i < n; i++ {
+		s = append(s, timeinterval.NewCompactTimeInterval(s[0].Start+int64(i)*int64(time.Minute), s[0].End+int64(i)*int64(time.Minute)))
+	}
+
+	footprint := func(b *testing.B, build func() any) {
+		b.ReportAllocs()
+
+		var live any
+		heap := uint64(0)
+		for i := 0; i < b.N; i++ {
+			var before, after runtime.MemStats
+
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			live = build()
+
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+
+			heap += after.HeapAlloc - before.HeapAlloc
+		}
+		runtime.KeepAlive(live)
+
+		b.ReportMetric(float64(heap)/float64(b.N)/n, "B/element")
+	}
+
+	b.Run("TimeIntervalSet", func(b *testing.B) {
+		footprint(b, func() any {
+			return timeinterval.NewTimeIntervalSetFromCompact(s)
+		})
+	})
+
+	b.Run("ColumnarTimeIntervalSet", func(b *testing.B) {
+		footprint(b, func() any {
+			ret := timeinterval.NewColumnarTimeIntervalSet()
+			ret.Add(s...)
+			return ret
+		})
+	})
+}
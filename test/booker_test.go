@@ -0,0 +1,210 @@
+package timeinterval_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestBookerReserve(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti23 := timeinterval.NewTimeInterval(t2, t3)
+	ti34 := timeinterval.NewTimeInterval(t3, t4)
+
+	b := timeinterval.NewBooker()
+	b.AddResource("room", 1)
+
+	assert.Panics(t, func() { b.AddResource("room", 1) })
+	assert.Panics(t, func() { b.AddResource("zero", 0) })
+	assert.Panics(t, func() { _ = b.Reserve("unknown", ti12) })
+
+	assert.Nil(t, b.Reserve("room", ti12))
+	assert.Nil(t, b.Reserve("room", ti34))
+	assert.Nil(t, b.Reserve("room", ti23))
+
+	err := b.Reserve("room", ti13)
+	var conflict *timeinterval.ConflictError
+	assert.Equal(t, errors.As(err, &conflict), true)
+	assert.Equal(t, conflict.Resource, "room")
+	assert.Equal(t, conflict.Interval.Equal(ti13), true)
+	assert.Equal(t, len(conflict.Conflicts), 2)
+	assert.Equal(t, conflict.Conflicts[0].Equal(ti12), true)
+	assert.Equal(t, conflict.Conflicts[1].Equal(ti23), true)
+
+	empty := timeinterval.NewEmptyTimeInterval()
+	assert.Equal(t, b.Check("room", empty), timeinterval.ErrEmptyTimeInterval)
+	assert.Equal(t, b.Reserve("room", empty), timeinterval.ErrEmptyTimeInterval)
+	assert.Equal(t, b.Move("room", ti34, empty), timeinterval.ErrEmptyTimeInterval)
+
+	tis := b.Reservations("room")
+	assert.Equal(t, len(tis.Elements()), 3)
+	assert.Equal(t, tis.Elements()[0].Equal(ti12), true)
+	assert.Equal(t, tis.Elements()[1].Equal(ti23), true)
+	assert.Equal(t, tis.Elements()[2].Equal(ti34), true)
+}
+
+func TestBookerCapacity(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti14 := timeinterval.NewTimeInterval(t1, t4)
+	ti34 := timeinterval.NewTimeInterval(t3, t4)
+	ti24 := timeinterval.NewTimeInterval(t2, t4)
+
+	b := timeinterval.NewBooker()
+	b.AddResource("projector", 2)
+
+	assert.Equal(t, b.Capacity("projector"), 2)
+
+	assert.Nil(t, b.Reserve("projector", ti12))
+	assert.Nil(t, b.Reserve("projector", ti34))
+	assert.Nil(t, b.Reserve("projector", ti14))
+
+	// [t1, t2) and [t3, t4) are full, [t2, t3) still has room
+	assert.Nil(t, b.Check("projector", timeinterval.NewTimeInterval(t2, t3)))
+
+	err := b.Reserve("projector", ti13)
+	var conflict *timeinterval.ConflictError
+	assert.Equal(t, errors.As(err, &conflict), true)
+	assert.Equal(t, len(conflict.Conflicts), 2)
+	assert.Equal(t, conflict.Conflicts[0].Equal(ti12), true)
+	assert.Equal(t, conflict.Conflicts[1].Equal(ti14), true)
+
+	assert.NotNil(t, b.Reserve("projector", ti24))
+	assert.Equal(t, len(b.Reservations("projector").Elements()), 3)
+}
+
+func TestBookerZeroDuration(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti22 := timeinterval.NewTimeInterval(t2, t2)
+	ti33 := timeinterval.NewTimeInterval(t3, t3)
+
+	// an instant strictly within a reservation overlaps it, as ConcurrentTimeIntervalSet.AddIfFree()
+	b := timeinterval.NewBooker()
+	b.AddResource("room", 1)
+	assert.Nil(t, b.Reserve("room", ti13))
+
+	var conflict *timeinterval.ConflictError
+	assert.Equal(t, errors.As(b.Reserve("room", ti22), &conflict), true)
+	assert.Equal(t, len(conflict.Conflicts), 1)
+	assert.Equal(t, conflict.Conflicts[0].Equal(ti13), true)
+	assert.Nil(t, b.Reserve("room", ti33))
+
+	cts := timeinterval.NewConcurrentTimeIntervalSet()
+	assert.Equal(t, cts.AddIfFree(ti13), true)
+	assert.Equal(t, cts.AddIfFree(ti22), false)
+	assert.Equal(t, cts.AddIfFree(ti33), true)
+
+	// and the other way round
+	b = timeinterval.NewBooker()
+	b.AddResource("room", 1)
+	assert.Nil(t, b.Reserve("room", ti22))
+	assert.Nil(t, b.Reserve("room", ti22))
+	assert.Equal(t, errors.As(b.Reserve("room", ti13), &conflict), true)
+	assert.Equal(t, len(conflict.Conflicts), 2)
+	assert.Equal(t, conflict.Conflicts[0].Equal(ti22), true)
+
+	// the instant counts with the reservations containing it
+	b = timeinterval.NewBooker()
+	b.AddResource("projector", 2)
+	assert.Nil(t, b.Reserve("projector", ti13))
+	assert.Nil(t, b.Reserve("projector", ti22))
+	assert.Nil(t, b.Reserve("projector", ti22))
+	assert.Equal(t, errors.As(b.Reserve("projector", timeinterval.NewTimeInterval(t1, t2)), &conflict), false)
+	assert.Equal(t, errors.As(b.Reserve("projector", ti13), &conflict), true)
+	assert.Equal(t, len(conflict.Conflicts), 4)
+}
+
+func TestBookerReleaseMove(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti23 := timeinterval.NewTimeInterval(t2, t3)
+	ti34 := timeinterval.NewTimeInterval(t3, t4)
+
+	b := timeinterval.NewBooker()
+	b.AddResource("room", 1)
+
+	assert.Nil(t, b.Reserve("room", ti12))
+	assert.Nil(t, b.Reserve("room", ti23))
+
+	// ti13 overlaps ti23, so ti12 has to stay where it was
+	var conflict *timeinterval.ConflictError
+	assert.Equal(t, errors.As(b.Move("room", ti12, ti13), &conflict), true)
+	assert.Equal(t, len(conflict.Conflicts), 1)
+	assert.Equal(t, conflict.Conflicts[0].Equal(ti23), true)
+	assert.Equal(t, b.Reservations("room").Elements()[0].Equal(ti12), true)
+
+	assert.Equal(t, b.Move("room", ti34, ti12), timeinterval.ErrNotReserved)
+
+	assert.Equal(t, b.Release("room", ti23), true)
+	assert.Equal(t, b.Release("room", ti23), false)
+	assert.Nil(t, b.Move("room", ti12, ti13))
+
+	tis := b.Reservations("room")
+	assert.Equal(t, len(tis.Elements()), 1)
+	assert.Equal(t, tis.Elements()[0].Equal(ti13), true)
+}
+
+func TestBookerConcurrent(t *testing.T) {
+	b := timeinterval.NewBooker()
+	b.AddResource("room", 3)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ti := timeinterval.NewTimeInterval(
+				timeinterval.NewTimePoint(year, month, day, 19, i%10, 0, 0),
+				timeinterval.NewTimePoint(year, month, day, 19, i%10+5, 0, 0),
+			)
+			if b.Reserve("room", ti) == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	tis := b.Reservations("room")
+	assert.Equal(t, len(tis.Elements()), succeeded)
+
+	// the depth is at its maximum at the start of some reservation
+	for _, ti := range tis.Elements() {
+		depth := 0
+		for _, other := range tis.Elements() {
+			if other.Has(ti.Start()) && ti.Start().Before(other.End()) {
+				depth++
+			}
+		}
+		assert.LessOrEqual(t, depth, 3)
+	}
+}
@@ -0,0 +1,222 @@
+package timeinterval_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestCSVIntervalReader(t *testing.T) {
+	input := `id,begin,finish
+1,2024-02-11T19:00:00Z,2024-02-11T19:01:00Z
+2,2024-02-11 19:02:00,2024-02-11 19:03:00
+3,2024-02-11T19:05:00Z,2024-02-11T19:04:00Z
+4,yesterday,2024-02-11T19:04:00Z
+5
+6,2024-02-11T19:04:00+09:00,2024-02-11T19:06:00+09:00
+`
+
+	m := &timeinterval.ColumnMapping{
+		Start:   "begin",
+		End:     "finish",
+		Layouts: []string{time.RFC3339, "2006-01-02 15:04:05"},
+	}
+
+	tis := timeinterval.NewTimeIntervalSet()
+	rowErrs, err := timeinterval.ReadIntoSet(timeinterval.NewCSVIntervalReader(strings.NewReader(input), m), tis)
+	assert.Nil(t, err)
+
+	assert.Equal(t, len(tis.Elements()), 3)
+	assert.Equal(t, tis.Elements()[0].Equal(timeinterval.NewTimeInterval(
+		timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0),
+		timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0),
+	)), true)
+	assert.Equal(t, tis.Elements()[1].Start().Equal(timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)), true)
+	assert.Equal(t, tis.Elements()[2].Start().Equal(timeinterval.NewTimePoint(year, month, day, 10, 4, 0, 0)), true)
+
+	assert.Equal(t, len(rowErrs), 3)
+	assert.Equal(t, rowErrs[0].Line, 4)
+	assert.Contains(t, rowErrs[0].Error(), "end is before start")
+	assert.Equal(t, rowErrs[1].Line, 5)
+	assert.Contains(t, rowErrs[1].Error(), "invalid timestamp")
+	assert.Equal(t, rowErrs[2].Line, 6)
+	assert.Contains(t, rowErrs[2].Error(), "too few columns")
+
+	// the header error is terminal, the rows are not read as the header
+	r := timeinterval.NewCSVIntervalReader(strings.NewReader("a,b\nbegin,finish\n2024-02-11T19:00:00Z,2024-02-11T19:01:00Z\n"), m)
+	_, err = r.Read()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "no such column")
+	assert.Equal(t, errors.As(err, new(*timeinterval.RowError)), false)
+	_, err2 := r.Read()
+	assert.Equal(t, err2, err)
+
+	assert.Panics(t, func() {
+		timeinterval.NewCSVIntervalReader(strings.NewReader(""), &timeinterval.ColumnMapping{Start: "a"})
+	})
+}
+
+func TestCSVIntervalReaderEpochDuration(t *testing.T) {
+	input := "1707678000000,60\n1707678120000,1.5\n1707678300000,-1\n"
+
+	m := &timeinterval.ColumnMapping{
+		Start:     "0",
+		Duration:  "1",
+		EpochUnit: timeinterval.EpochMilliseconds,
+		NoHeader:  true,
+	}
+
+	r := timeinterval.NewCSVIntervalReader(strings.NewReader(input), m)
+
+	ti, err := r.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, ti.Start().Equal(timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)), true)
+	assert.Equal(t, ti.Duration(), time.Minute)
+
+	ti, err = r.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, ti.Start().Equal(timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)), true)
+	assert.Equal(t, ti.Duration(), time.Millisecond*1500)
+
+	_, err = r.Read()
+	var rowErr *timeinterval.RowError
+	assert.Equal(t, errors.As(err, &rowErr), true)
+	assert.Equal(t, rowErr.Line, 3)
+
+	_, err = r.Read()
+	assert.Equal(t, err, io.EOF)
+
+	// out of the range of int64 nanoseconds, 1678 to 2262
+	m = &timeinterval.ColumnMapping{
+		Start:     "0",
+		End:       "1",
+		EpochUnit: timeinterval.EpochSeconds,
+		NoHeader:  true,
+	}
+
+	r = timeinterval.NewCSVIntervalReader(strings.NewReader("-20000000000,-19999999999.5\n"), m)
+
+	ti, err = r.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, ti.Start().Equal(timeinterval.FromUnix(-20000000000, 0)), true)
+	assert.Equal(t, ti.Start().ToTime().Year(), 1336)
+	assert.Equal(t, ti.Duration(), time.Millisecond*500)
+}
+
+func TestCSVIntervalReaderNumericRows(t *testing.T) {
+	input := `1707678000,60
+3/2,60
+1/2,60
+0x10,60
+1e3,60
+1707678000,NaN
+1707678000,Inf
+1707678000,-Inf
+1707678000,0x10
+1707678000,99999999999999999999
+1707678000,1e400
+`
+
+	m := &timeinterval.ColumnMapping{
+		Start:     "0",
+		Duration:  "1",
+		EpochUnit: timeinterval.EpochSeconds,
+		NoHeader:  true,
+	}
+
+	tis := timeinterval.NewTimeIntervalSet()
+	rowErrs, err := timeinterval.ReadIntoSet(timeinterval.NewCSVIntervalReader(strings.NewReader(input), m), tis)
+	assert.Nil(t, err)
+	assert.Equal(t, len(tis.Elements()), 1)
+
+	lines := []int{}
+	for _, v := range rowErrs {
+		lines = append(lines, v.Line)
+	}
+	assert.Equal(t, lines, []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11})
+
+	for _, v := range rowErrs[:4] {
+		assert.Contains(t, v.Error(), "invalid timestamp")
+	}
+	assert.Contains(t, rowErrs[8].Error(), "duration out of range")
+}
+
+func TestJSONLinesIntervalReader(t *testing.T) {
+	input := `{"period": {"from": 1707678000, "length": "1m"}}
+
+{"period": {"from": "1707678060.5", "length": 30}}
+{"period": {"from": 1707678120}}
+not json
+{"period": {"from": "2024-02-11T19:03:00Z", "length": "2m"}}
+`
+
+	m := &timeinterval.ColumnMapping{
+		Start:     "period.from",
+		Duration:  "period.length",
+		EpochUnit: timeinterval.EpochSeconds,
+	}
+
+	tis := timeinterval.NewTimeIntervalSet()
+	rowErrs, err := timeinterval.ReadIntoSet(timeinterval.NewJSONLinesIntervalReader(strings.NewReader(input), m), tis)
+	assert.Nil(t, err)
+
+	assert.Equal(t, len(tis.Elements()), 3)
+	assert.Equal(t, tis.Elements()[0].Duration(), time.Minute)
+	assert.Equal(t, tis.Elements()[1].Start().Equal(timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 500000000)), true)
+	assert.Equal(t, tis.Elements()[1].Duration(), time.Second*30)
+	assert.Equal(t, tis.Elements()[2].Duration(), time.Minute*2)
+
+	assert.Equal(t, len(rowErrs), 2)
+	assert.Equal(t, rowErrs[0].Line, 4)
+	assert.Contains(t, rowErrs[0].Error(), "no such field")
+	assert.Equal(t, rowErrs[1].Line, 5)
+}
+
+func TestIntervalWriterRoundTrip(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 250000000)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(timeinterval.NewTimeInterval(t1, t2), timeinterval.NewTimeInterval(t2, t3))
+
+	mappings := []*timeinterval.ColumnMapping{
+		{Start: "start", End: "end"},
+		{Start: "start", End: "end", EpochUnit: timeinterval.EpochSeconds},
+		{Start: "start", Duration: "duration", EpochUnit: timeinterval.EpochMilliseconds, DurationUnit: time.Millisecond},
+		{Start: "0", Duration: "1", NoHeader: true},
+	}
+
+	for _, m := range mappings {
+		var csvBuf, jsonBuf bytes.Buffer
+
+		assert.Nil(t, timeinterval.WriteSet(timeinterval.NewCSVIntervalWriter(&csvBuf, m), tis))
+		assert.Nil(t, timeinterval.WriteSet(timeinterval.NewJSONLinesIntervalWriter(&jsonBuf, m), tis))
+
+		for _, r := range []timeinterval.IntervalReader{
+			timeinterval.NewCSVIntervalReader(&csvBuf, m),
+			timeinterval.NewJSONLinesIntervalReader(&jsonBuf, m),
+		} {
+			got := timeinterval.NewTimeIntervalSet()
+			rowErrs, err := timeinterval.ReadIntoSet(r, got)
+			assert.Nil(t, err)
+			assert.Equal(t, len(rowErrs), 0)
+			assert.Equal(t, len(got.Elements()), 2)
+			for i, ti := range got.Elements() {
+				assert.Equal(t, ti.Equal(tis.Elements()[i]), true)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	m := &timeinterval.ColumnMapping{Start: "s", End: "e", EpochUnit: timeinterval.EpochSeconds}
+	assert.Nil(t, timeinterval.WriteSet(timeinterval.NewJSONLinesIntervalWriter(&buf, m), tis))
+	assert.Equal(t, strings.Split(buf.String(), "\n")[0], `{"e":1707678060.25,"s":1707678000}`)
+}
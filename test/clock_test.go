@@ -0,0 +1,74 @@
+package timeinterval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestFakeClock(t *testing.T) {
+	now := time.Date(2024, 6, 5, 9, 0, 0, 0, time.UTC)
+	fc := timeinterval.NewFakeClock(now)
+	assert.Equal(t, fc.Now(), now)
+
+	t1 := fc.NewTimer(time.Minute * 2)
+	t2 := fc.NewTimer(time.Minute)
+	t3 := fc.NewTimer(time.Minute * 3)
+	fc.BlockUntil(3)
+
+	assert.Equal(t, t3.Stop(), true)
+	assert.Equal(t, t3.Stop(), false)
+
+	fc.Advance(time.Minute)
+	assert.Equal(t, <-t2.C(), now.Add(time.Minute))
+	assert.Equal(t, len(t1.C()), 0)
+
+	fc.Set(now.Add(time.Hour))
+	assert.Equal(t, <-t1.C(), now.Add(time.Hour))
+	assert.Equal(t, t1.Stop(), false)
+	assert.Equal(t, len(t3.C()), 0)
+
+	assert.Equal(t, <-fc.NewTimer(0).C(), now.Add(time.Hour))
+
+	assert.Panics(t, func() {
+		fc.Set(now)
+	})
+
+	rt := timeinterval.RealClock{}.NewTimer(time.Millisecond)
+	<-rt.C()
+	assert.Equal(t, rt.Stop(), false)
+}
+
+func TestClockHelpers(t *testing.T) {
+	now := time.Date(2024, 6, 5, 9, 0, 0, 0, time.UTC)
+	fc := timeinterval.NewFakeClock(now)
+
+	assert.Equal(t, timeinterval.Now(fc).Equal(timeinterval.NewTimePoint(2024, 6, 5, 9, 0, 0, 0)), true)
+
+	tp := timeinterval.NewTimePoint(2024, 6, 5, 8, 0, 0, 0)
+	assert.Equal(t, timeinterval.Since(fc, tp), time.Hour)
+	assert.Equal(t, timeinterval.Until(fc, tp), -time.Hour)
+
+	last24h := timeinterval.RelativeInterval(fc, -time.Hour*24, 0)
+	assert.Equal(t, last24h.String(), "2024-06-04T09:00:00Z/2024-06-05T09:00:00Z")
+	assert.Equal(t, timeinterval.RelativeInterval(fc, time.Hour, time.Hour*2).String(), "2024-06-05T10:00:00Z/2024-06-05T11:00:00Z")
+	assert.Panics(t, func() {
+		timeinterval.RelativeInterval(fc, 0, -time.Hour)
+	})
+
+	ti := timeinterval.NewTimeInterval(tp, timeinterval.NewTimePoint(2024, 6, 5, 10, 0, 0, 0))
+	assert.Equal(t, timeinterval.ActiveNow(fc, ti), true)
+	assert.Equal(t, timeinterval.ActiveNow(fc, last24h), false)
+
+	fc.Advance(time.Hour)
+	assert.Equal(t, timeinterval.ActiveNow(fc, ti), false)
+	assert.Equal(t, timeinterval.Since(fc, tp), time.Hour*2)
+
+	// the real clock
+	var c timeinterval.Clock = timeinterval.RealClock{}
+	assert.Equal(t, timeinterval.ActiveNow(c, timeinterval.RelativeInterval(c, -time.Minute, time.Minute)), true)
+	assert.Less(t, timeinterval.Since(c, timeinterval.Now(c)), time.Minute)
+}
@@ -0,0 +1,75 @@
+package timeinterval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestClusters(t *testing.T) {
+	at := func(min int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, day, 10, min, 0, 0)
+	}
+	ping := func(min int) *timeinterval.TimeInterval {
+		return timeinterval.NewTimeInterval(at(min), at(min))
+	}
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(ping(20), ping(0), ping(5), timeinterval.NewTimeInterval(at(8), at(12)), ping(50), ping(42), ping(35))
+
+	clusters := tis.Clusters(time.Minute * 10)
+	assert.Equal(t, len(clusters), 2)
+
+	assert.Equal(t, clusters[0].Span.String(), timeinterval.NewTimeInterval(at(0), at(20)).String())
+	assert.Equal(t, clusters[0].Members, []*timeinterval.TimeInterval{ping(0), ping(5), timeinterval.NewTimeInterval(at(8), at(12)), ping(20)})
+
+	assert.Equal(t, clusters[1].Span.String(), timeinterval.NewTimeInterval(at(35), at(50)).String())
+	assert.Equal(t, clusters[1].Members, []*timeinterval.TimeInterval{ping(35), ping(42), ping(50)})
+
+	// not modified
+	assert.Equal(t, tis.Elements()[0], ping(20))
+
+	// appending to a member list does not change the next one
+	_ = append(clusters[0].Members, ping(59))
+	assert.Equal(t, clusters[1].Members[0], ping(35))
+
+	// a gap of 8 minutes is not merged with a tolerance of 7 minutes
+	clusters = tis.Clusters(time.Minute * 7)
+	assert.Equal(t, len(clusters), 4)
+	assert.Equal(t, clusters[1].Members, []*timeinterval.TimeInterval{ping(20)})
+
+	assert.Equal(t, len(timeinterval.NewTimeIntervalSet().Clusters(time.Hour)), 0)
+
+	assert.Panics(t, func() {
+		tis.Clusters(-time.Second)
+	})
+}
+
+func TestCoalesce(t *testing.T) {
+	at := func(min int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, day, 10, min, 0, 0)
+	}
+	interval := func(m1, m2 int) *timeinterval.TimeInterval {
+		return timeinterval.NewTimeInterval(at(m1), at(m2))
+	}
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(interval(30, 40), interval(0, 10), interval(12, 15), interval(15, 20), interval(5, 8))
+
+	// same as Cleanup(false)
+	tis2 := tis.Copy()
+	tis2.Coalesce(0)
+	tis3 := tis.Copy()
+	tis3.Cleanup(false)
+	assert.Equal(t, tis2.String(), tis3.String())
+
+	// the gap of 2 minutes is merged, the gap of 10 minutes is not
+	tis.Coalesce(time.Minute * 2)
+	assert.Equal(t, tis.Elements(), []*timeinterval.TimeInterval{interval(0, 20), interval(30, 40)})
+
+	tis.Coalesce(time.Hour)
+	assert.Equal(t, tis.Elements(), []*timeinterval.TimeInterval{interval(0, 40)})
+}
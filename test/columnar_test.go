@@ -0,0 +1,94 @@
+package timeinterval_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestColumnarTimeIntervalSet(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(timeinterval.NewTimeInterval(t2, t3), timeinterval.NewEmptyTimeInterval(), timeinterval.NewTimeInterval(t1, t2))
+
+	ctis := tis.Columnar()
+	assert.Equal(t, ctis.Len(), 2)
+	assert.Equal(t, ctis.At(0), timeinterval.NewCompactTimeInterval(t2.UnixNano(), t3.UnixNano()))
+	assert.Equal(t, ctis.Starts(), []int64{t2.UnixNano(), t1.UnixNano()})
+	assert.Equal(t, ctis.Ends(), []int64{t3.UnixNano(), t2.UnixNano()})
+	assert.Equal(t, ctis.Duration(), time.Minute*2)
+
+	ctis2 := ctis.Copy()
+	ctis2.Cleanup(false)
+	assert.Equal(t, ctis2.Len(), 1)
+	assert.Equal(t, ctis.Len(), 2)
+	assert.Equal(t, ctis2.TimeIntervalSet().Elements(), []*timeinterval.TimeInterval{timeinterval.NewTimeInterval(t1, t3)})
+
+	ctis2.Merge(ctis)
+	assert.Equal(t, ctis2.Len(), 3)
+
+	span, ok := ctis.Span()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, span, timeinterval.NewCompactTimeInterval(t1.UnixNano(), t3.UnixNano()))
+
+	ctis2.Clear()
+	assert.Equal(t, ctis2.Len(), 0)
+	assert.Equal(t, len(ctis2.TimeIntervalSet().Elements()), 0)
+	_, ok = ctis2.Span()
+	assert.Equal(t, ok, false)
+}
+
+func TestColumnarTimeIntervalSetDurationOverflow(t *testing.T) {
+	long := timeinterval.NewTimeIntervalSet()
+	long.Add(timeinterval.NewTimeInterval(timeinterval.NewTimePoint(1700, 1, 1, 0, 0, 0, 0), timeinterval.NewTimePoint(2200, 1, 1, 0, 0, 0, 0)))
+	long.Add(timeinterval.NewTimeInterval(timeinterval.NewTimePoint(2200, 1, 1, 0, 0, 0, 0), timeinterval.NewTimePoint(2200, 1, 1, 0, 0, 0, 5)))
+
+	ctis := long.Columnar()
+
+	assert.Equal(t, ctis.Duration(), long.Duration())
+	assert.Equal(t, ctis.Duration(), time.Duration(math.MaxInt64))
+	assert.Equal(t, ctis.WideDuration(), long.WideDuration())
+
+	_, err := ctis.DurationChecked()
+	assert.Equal(t, err, timeinterval.ErrDurationOverflow)
+}
+
+func TestColumnarTimeIntervalSetRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 200; n++ {
+		a, b := randomSet(rnd), randomSet(rnd)
+		ca, cb := a.Columnar(), b.Columnar()
+
+		assertSameSet(t, ca.TimeIntervalSet(), a)
+		assert.Equal(t, ca.Duration(), a.Duration())
+		assert.Equal(t, ca.WideDuration(), a.WideDuration())
+		if span, ok := ca.Span(); ok {
+			assert.Equal(t, span.TimeInterval(), a.Span())
+		} else {
+			assert.Equal(t, a.Span().IsEmpty(), true)
+		}
+
+		for _, removeZeroDuration := range []bool{false, true} {
+			want := a.Copy()
+			want.Cleanup(removeZeroDuration)
+
+			got := ca.Copy()
+			got.Cleanup(removeZeroDuration)
+			assertSameSet(t, got.TimeIntervalSet(), want)
+		}
+
+		assertSameSet(t, ca.Union(cb).TimeIntervalSet(), a.Union(b))
+		assertSameSet(t, ca.Intersect(cb).TimeIntervalSet(), a.Intersect(b))
+		assertSameSet(t, ca.Subtract(cb).TimeIntervalSet(), a.Subtract(b))
+		assertSameSet(t, ca.Gaps().TimeIntervalSet(), a.Gaps())
+	}
+}
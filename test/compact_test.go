@@ -0,0 +1,92 @@
+package timeinterval_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestCompactTimeInterval(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+
+	ti := timeinterval.NewTimeInterval(t1, t2)
+	c := timeinterval.CompactOf(ti)
+
+	assert.Equal(t, c, timeinterval.NewCompactTimeInterval(t1.UnixNano(), t2.UnixNano()))
+	assert.Equal(t, c.Duration(), time.Minute)
+	assert.Equal(t, c.TimeInterval(), ti)
+	assert.Equal(t, c.IsZeroDuration(), false)
+
+	c2 := timeinterval.NewCompactTimeInterval(c.End, c.End)
+	assert.Equal(t, c.Compare(c2), -1)
+	assert.Equal(t, c2.Compare(c), 1)
+	assert.Equal(t, c.Compare(c), 0)
+	assert.Equal(t, c.Intersects(c2), false)
+	assert.Equal(t, c.Mergeable(c2), true)
+
+	// saturated as TimeInterval.Duration()
+	long := timeinterval.NewTimeInterval(timeinterval.NewTimePoint(1700, 1, 1, 0, 0, 0, 0), timeinterval.NewTimePoint(2200, 1, 1, 0, 0, 0, 0))
+	assert.Equal(t, timeinterval.CompactOf(long).Duration(), long.Duration())
+	assert.Equal(t, timeinterval.CompactOf(long).Duration(), time.Duration(math.MaxInt64))
+	assert.Equal(t, timeinterval.NewCompactTimeInterval(math.MinInt64, math.MaxInt64).Duration(), time.Duration(math.MaxInt64))
+
+	assert.Panics(t, func() { timeinterval.NewCompactTimeInterval(2, 1) })
+	assert.Panics(t, func() { timeinterval.CompactOf(timeinterval.NewEmptyTimeInterval()) })
+	assert.Panics(t, func() {
+		timeinterval.CompactOf(timeinterval.NewTimeInterval(timeinterval.NewTimePoint(1600, 1, 1, 0, 0, 0, 0), t1))
+	})
+}
+
+func TestCleanupCompact(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 200; n++ {
+		tis := randomSet(rnd)
+
+		for _, removeZeroDuration := range []bool{false, true} {
+			want := tis.Copy()
+			want.Cleanup(removeZeroDuration)
+
+			got := timeinterval.CleanupCompact(tis.Compact(), removeZeroDuration)
+			assertSameSet(t, timeinterval.NewTimeIntervalSetFromCompact(got), want)
+		}
+	}
+}
+
+func TestCompactAllocations(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	s := make([]timeinterval.CompactTimeInterval, 1000)
+	buf := make([]timeinterval.CompactTimeInterval, len(s))
+	for i := range s {
+		start := rnd.Int63n(int64(time.Hour))
+		s[i] = timeinterval.NewCompactTimeInterval(start, start+rnd.Int63n(int64(time.Minute)))
+	}
+
+	assert.Equal(t, testing.AllocsPerRun(10, func() {
+		copy(buf, s)
+		timeinterval.SortCompact(buf)
+	}), 0.0)
+
+	assert.Equal(t, testing.AllocsPerRun(10, func() {
+		copy(buf, s)
+		timeinterval.CleanupCompact(buf, true)
+	}), 0.0)
+
+	assert.Equal(t, testing.AllocsPerRun(10, func() {
+		_ = s[0].Compare(s[1]) + s[1].Compare(s[2])
+	}), 0.0)
+
+	// Cleanup sorts and merges in place, so cleaning up a clean set does not allocate either
+	tis := timeinterval.NewTimeIntervalSetFromCompact(s)
+	tis.Cleanup(true)
+	assert.Equal(t, testing.AllocsPerRun(10, func() {
+		tis.Cleanup(true)
+	}), 0.0)
+}
@@ -0,0 +1,112 @@
+package timeinterval_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestConcurrentTimeIntervalSetAddIfFree(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti23 := timeinterval.NewTimeInterval(t2, t3)
+
+	ctis := timeinterval.NewConcurrentTimeIntervalSet()
+
+	assert.Equal(t, ctis.AddIfFree(ti12), true)
+	assert.Equal(t, ctis.AddIfFree(ti13), false)
+	assert.Equal(t, ctis.AddIfFree(ti23), true)
+	assert.Equal(t, ctis.Len(), 2)
+	assert.Equal(t, ctis.Duration(), time.Minute*2)
+
+	ctis.Cleanup(true)
+	assert.Equal(t, ctis.Len(), 1)
+	assert.Equal(t, ctis.Elements()[0].Equal(ti13), true)
+
+	ctis.Clear()
+	assert.Equal(t, ctis.Len(), 0)
+}
+
+func TestConcurrentTimeIntervalSetSubtractAndReturn(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+	t5 := timeinterval.NewTimePoint(year, month, day, 19, 4, 0, 0)
+	t6 := timeinterval.NewTimePoint(year, month, day, 19, 5, 0, 0)
+
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti46 := timeinterval.NewTimeInterval(t4, t6)
+	ti25 := timeinterval.NewTimeInterval(t2, t5)
+
+	ctis := timeinterval.NewConcurrentTimeIntervalSet()
+	ctis.Add(ti13, ti46)
+
+	removed := ctis.SubtractAndReturn(ti25)
+	assert.Equal(t, len(removed.Elements()), 2)
+	assert.Equal(t, removed.Elements()[0].Equal(timeinterval.NewTimeInterval(t2, t3)), true)
+	assert.Equal(t, removed.Elements()[1].Equal(timeinterval.NewTimeInterval(t4, t5)), true)
+
+	rest := ctis.Snapshot()
+	assert.Equal(t, len(rest.Elements()), 2)
+	assert.Equal(t, rest.Elements()[0].Equal(timeinterval.NewTimeInterval(t1, t2)), true)
+	assert.Equal(t, rest.Elements()[1].Equal(timeinterval.NewTimeInterval(t5, t6)), true)
+
+	// a snapshot is not affected by later changes
+	ctis.Clear()
+	assert.Equal(t, len(rest.Elements()), 2)
+}
+
+func TestConcurrentTimeIntervalSetStress(t *testing.T) {
+	ctis := timeinterval.NewConcurrentTimeIntervalSet()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	added := time.Duration(0)
+
+	for i := 0; i < 60; i++ {
+		wg.Add(3)
+
+		go func(i int) {
+			defer wg.Done()
+
+			ti := timeinterval.NewTimeInterval(
+				timeinterval.NewTimePoint(year, month, day, 19, i, 0, 0),
+				timeinterval.NewTimePoint(year, month, day, 19, i, 30, 0),
+			)
+			if ctis.AddIfFree(ti) {
+				mu.Lock()
+				added += ti.Duration()
+				mu.Unlock()
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			_ = ctis.Duration()
+			_ = ctis.Snapshot().Duration()
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			ctis.Update(func(tis *timeinterval.TimeIntervalSet) {
+				tis.Sort()
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, ctis.Len(), 60)
+	assert.Equal(t, ctis.Duration(), added)
+	assert.Equal(t, ctis.Duration(), time.Minute*30)
+}
@@ -0,0 +1,153 @@
+package timeinterval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// Wednesday
+	ref := timeinterval.NewTimePoint(2024, 6, 5, 10, 30, 0, 0)
+
+	testCases := []struct {
+		expr string
+		next string
+		prev string
+	}{
+		{"*/15 * * * *", "2024-06-05T10:45:00Z", "2024-06-05T10:15:00Z"},
+		{"0 9 * * MON-FRI", "2024-06-06T09:00:00Z", "2024-06-05T09:00:00Z"},
+		{"30 10 * * *", "2024-06-06T10:30:00Z", "2024-06-04T10:30:00Z"},
+		{"*/10 * * * * *", "2024-06-05T10:30:10Z", "2024-06-05T10:29:50Z"},
+		{"0 0 1,15 * ?", "2024-06-15T00:00:00Z", "2024-06-01T00:00:00Z"},
+		{"0 8-18/4 * * *", "2024-06-05T12:00:00Z", "2024-06-05T08:00:00Z"},
+		{"0 0 * jan,JUL *", "2024-07-01T00:00:00Z", "2024-01-31T00:00:00Z"},
+		{"0 0 * * 7", "2024-06-09T00:00:00Z", "2024-06-02T00:00:00Z"},
+		{"@hourly", "2024-06-05T11:00:00Z", "2024-06-05T10:00:00Z"},
+		{"@daily", "2024-06-06T00:00:00Z", "2024-06-05T00:00:00Z"},
+		{"@weekly", "2024-06-09T00:00:00Z", "2024-06-02T00:00:00Z"},
+		{"@monthly", "2024-07-01T00:00:00Z", "2024-06-01T00:00:00Z"},
+		{"@yearly", "2025-01-01T00:00:00Z", "2024-01-01T00:00:00Z"},
+		{"0 0 L * *", "2024-06-30T00:00:00Z", "2024-05-31T00:00:00Z"},
+		{"0 0 L-1 * *", "2024-06-29T00:00:00Z", "2024-05-30T00:00:00Z"},
+		{"0 0 LW * *", "2024-06-28T00:00:00Z", "2024-05-31T00:00:00Z"},
+		{"0 0 15W * *", "2024-06-14T00:00:00Z", "2024-05-15T00:00:00Z"},
+		{"0 0 1W * *", "2024-07-01T00:00:00Z", "2024-06-03T00:00:00Z"},
+		{"0 0 * * 5L", "2024-06-28T00:00:00Z", "2024-05-31T00:00:00Z"},
+		{"0 0 * * FRI#2", "2024-06-14T00:00:00Z", "2024-05-10T00:00:00Z"},
+		// either day field
+		{"0 0 13 * 5", "2024-06-07T00:00:00Z", "2024-05-31T00:00:00Z"},
+		// both day fields, since day of month starts with *
+		{"0 0 */2 * 1", "2024-06-17T00:00:00Z", "2024-06-03T00:00:00Z"},
+		{"0 12 29 FEB *", "2028-02-29T12:00:00Z", "2024-02-29T12:00:00Z"},
+	}
+
+	for _, tc := range testCases {
+		cs, err := timeinterval.ParseCron(tc.expr, time.UTC)
+		if !assert.Nil(t, err, tc.expr) {
+			continue
+		}
+
+		assert.Equal(t, cs.String(), tc.expr)
+		assert.Equal(t, cs.Next(ref).String(), tc.next, tc.expr)
+		assert.Equal(t, cs.Prev(ref).String(), tc.prev, tc.expr)
+	}
+
+	cs, err := timeinterval.ParseCron("0 0 30 2 *", time.UTC)
+	assert.Nil(t, err)
+	assert.Nil(t, cs.Next(ref))
+	assert.Nil(t, cs.Prev(ref))
+}
+
+func TestCronScheduleDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	// 02:30 does not exist on 2024-03-10
+	cs, err := timeinterval.ParseCron("30 2 * * *", loc)
+	assert.Nil(t, err)
+
+	tp := cs.Next(timeinterval.FromTime(time.Date(2024, 3, 10, 0, 0, 0, 0, loc)))
+	assert.Equal(t, tp.String(), "2024-03-11T06:30:00Z")
+	assert.Equal(t, cs.Prev(tp).String(), "2024-03-09T07:30:00Z")
+
+	// 01:30 is repeated on 2024-11-03
+	cs, err = timeinterval.ParseCron("30 1 * * *", loc)
+	assert.Nil(t, err)
+
+	tp = cs.Next(timeinterval.FromTime(time.Date(2024, 11, 3, 0, 0, 0, 0, loc)))
+	assert.Equal(t, tp.String(), "2024-11-03T05:30:00Z")
+	assert.Equal(t, cs.Next(tp).String(), "2024-11-04T06:30:00Z")
+
+	// 09:00 in New York
+	cs, err = timeinterval.ParseCron("0 9 * * *", loc)
+	assert.Nil(t, err)
+	assert.Equal(t, cs.Location(), loc)
+	assert.Equal(t, cs.Next(timeinterval.NewTimePoint(2024, 6, 5, 0, 0, 0, 0)).String(), "2024-06-05T13:00:00Z")
+}
+
+func TestCronScheduleError(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 5m",
+		"0 0 L-40 * *",
+		"0 0 32W * *",
+		"0 0 * * 8",
+		"0 0 * * 1#6",
+		"0 0 * * L",
+		"0 0 * 13 *",
+	} {
+		_, err := timeinterval.ParseCron(expr, time.UTC)
+		assert.NotNil(t, err, expr)
+	}
+
+	assert.Panics(t, func() {
+		timeinterval.ParseCron("* * * * *", nil)
+	})
+}
+
+func TestCronScheduleTimeIntervals(t *testing.T) {
+	cs, err := timeinterval.ParseCron("0 */6 * * *", time.UTC)
+	assert.Nil(t, err)
+
+	bound := timeinterval.NewTimeInterval(
+		timeinterval.NewTimePoint(2024, 6, 5, 5, 0, 0, 0),
+		timeinterval.NewTimePoint(2024, 6, 5, 19, 0, 0, 0),
+	)
+
+	got := []string{}
+	for _, tp := range cs.TimePoints(bound) {
+		got = append(got, tp.String())
+	}
+	assert.Equal(t, got, []string{"2024-06-05T06:00:00Z", "2024-06-05T12:00:00Z", "2024-06-05T18:00:00Z"})
+
+	// the start is inclusive and the end is exclusive
+	assert.Equal(t, len(cs.TimePoints(timeinterval.NewTimeInterval(
+		timeinterval.NewTimePoint(2024, 6, 5, 6, 0, 0, 0),
+		timeinterval.NewTimePoint(2024, 6, 5, 12, 0, 0, 0),
+	))), 1)
+
+	assert.Equal(t, cs.TimeIntervals(bound, time.Hour*2).String(),
+		"[2024-06-05T06:00:00Z/2024-06-05T08:00:00Z, 2024-06-05T12:00:00Z/2024-06-05T14:00:00Z, 2024-06-05T18:00:00Z/2024-06-05T19:00:00Z]")
+
+	tis := cs.TimeIntervals(bound, time.Hour*8)
+	assert.Equal(t, tis.String(),
+		"[2024-06-05T05:00:00Z/2024-06-05T08:00:00Z, 2024-06-05T06:00:00Z/2024-06-05T14:00:00Z, 2024-06-05T12:00:00Z/2024-06-05T19:00:00Z, 2024-06-05T18:00:00Z/2024-06-05T19:00:00Z]")
+
+	tis.Cleanup(true)
+	assert.Equal(t, tis.Duration(), time.Hour*14)
+
+	assert.Panics(t, func() {
+		cs.TimeIntervals(bound, -time.Hour)
+	})
+}
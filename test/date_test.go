@@ -0,0 +1,137 @@
+package timeinterval_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestDateNew(t *testing.T) {
+	d1 := timeinterval.NewDate(2024, 2, 30)
+	d2 := timeinterval.NewDate(2024, 3, 1)
+
+	assert.Equal(t, d1.Equal(d2), true)
+	assert.Equal(t, d1.Year(), 2024)
+	assert.Equal(t, d1.Month(), 3)
+	assert.Equal(t, d1.Day(), 1)
+	assert.Equal(t, d1.Weekday(), time.Friday)
+
+	assert.Equal(t, d1.AddDays(-1).Equal(timeinterval.NewDate(2024, 2, 29)), true)
+	assert.Equal(t, d1.AddDays(-1).Before(d1), true)
+	assert.Equal(t, d1.AddDays(366).After(d1), true)
+}
+
+func TestDateTimeInterval(t *testing.T) {
+	utc := time.UTC
+	kst := time.FixedZone("KST", 9*60*60)
+
+	d := timeinterval.NewDate(year, month, day)
+
+	ti := d.TimeInterval(utc)
+	assert.Equal(t, ti.Start().Equal(timeinterval.NewTimePoint(year, month, day, 0, 0, 0, 0)), true)
+	assert.Equal(t, ti.Duration(), time.Hour*24)
+
+	ti = d.TimeInterval(kst)
+	assert.Equal(t, ti.Start().Equal(timeinterval.NewTimePoint(year, month, day-1, 15, 0, 0, 0)), true)
+	assert.Equal(t, ti.End().Equal(timeinterval.NewTimePoint(year, month, day, 15, 0, 0, 0)), true)
+}
+
+func TestDateInterval(t *testing.T) {
+	d1 := timeinterval.NewDate(2024, 3, 1)
+	d15 := timeinterval.NewDate(2024, 3, 15)
+
+	assert.Panics(t, func() { _ = timeinterval.NewDateInterval(d15, d1) })
+
+	di := timeinterval.NewDateInterval(d1, d15)
+	assert.Equal(t, di.Days(), 15)
+	assert.Equal(t, len(di.Dates()), 15)
+	assert.Equal(t, di.Dates()[14].Equal(d15), true)
+	assert.Equal(t, di.Has(d1), true)
+	assert.Equal(t, di.Has(d15), true)
+	assert.Equal(t, di.Has(d15.AddDays(1)), false)
+	assert.Equal(t, di.Has(d1.AddDays(-1)), false)
+
+	// the last day is included
+	ti := di.TimeInterval(time.UTC)
+	assert.Equal(t, ti.Duration(), time.Hour*24*15)
+	assert.Equal(t, ti.End().Equal(timeinterval.NewTimePoint(2024, 3, 16, 0, 0, 0, 0)), true)
+
+	assert.Equal(t, timeinterval.NewDateInterval(d1, d1).Days(), 1)
+}
+
+func TestTimeOfDayNew(t *testing.T) {
+	assert.Panics(t, func() { _ = timeinterval.NewTimeOfDay(24, 0, 0, 0) })
+	assert.Panics(t, func() { _ = timeinterval.NewTimeOfDay(0, 60, 0, 0) })
+	assert.Panics(t, func() { _ = timeinterval.NewTimeOfDay(0, 0, -1, 0) })
+
+	tod1 := timeinterval.NewTimeOfDay(9, 0, 0, 0)
+	tod2 := timeinterval.NewTimeOfDay(17, 30, 0, 0)
+
+	assert.Equal(t, tod1.Before(tod2), true)
+	assert.Equal(t, tod2.After(tod1), true)
+	assert.Equal(t, tod1.Equal(timeinterval.NewTimeOfDay(9, 0, 0, 0)), true)
+	assert.Equal(t, tod2.Hour(), 17)
+	assert.Equal(t, tod2.Minute(), 30)
+
+	tp := tod2.On(timeinterval.NewDate(year, month, day), time.UTC)
+	assert.Equal(t, tp.Equal(timeinterval.NewTimePoint(year, month, day, 17, 30, 0, 0)), true)
+}
+
+func TestTimeOfDayInterval(t *testing.T) {
+	tod0 := timeinterval.NewTimeOfDay(0, 0, 0, 0)
+	tod6 := timeinterval.NewTimeOfDay(6, 0, 0, 0)
+	tod9 := timeinterval.NewTimeOfDay(9, 0, 0, 0)
+	tod17 := timeinterval.NewTimeOfDay(17, 30, 0, 0)
+	tod22 := timeinterval.NewTimeOfDay(22, 0, 0, 0)
+
+	office := timeinterval.NewTimeOfDayInterval(tod9, tod17)
+	night := timeinterval.NewTimeOfDayInterval(tod22, tod6)
+	whole := timeinterval.NewTimeOfDayInterval(tod0, tod0)
+
+	assert.Equal(t, office.CrossesMidnight(), false)
+	assert.Equal(t, night.CrossesMidnight(), true)
+	assert.Equal(t, whole.CrossesMidnight(), true)
+
+	assert.Equal(t, office.Duration(), time.Hour*8+time.Minute*30)
+	assert.Equal(t, night.Duration(), time.Hour*8)
+	assert.Equal(t, whole.Duration(), time.Hour*24)
+
+	assert.Equal(t, office.Has(tod9), true)
+	assert.Equal(t, office.Has(tod17), false)
+	assert.Equal(t, night.Has(tod0), true)
+	assert.Equal(t, night.Has(tod22), true)
+	assert.Equal(t, night.Has(tod6), false)
+	assert.Equal(t, night.Has(tod9), false)
+
+	d := timeinterval.NewDate(year, month, day)
+	ti := night.On(d, time.UTC)
+	assert.Equal(t, ti.Start().Equal(timeinterval.NewTimePoint(year, month, day, 22, 0, 0, 0)), true)
+	assert.Equal(t, ti.End().Equal(timeinterval.NewTimePoint(year, month, day+1, 6, 0, 0, 0)), true)
+}
+
+func TestTimeOfDayIntervalTimeIntervals(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	office := timeinterval.NewTimeOfDayInterval(timeinterval.NewTimeOfDay(9, 0, 0, 0), timeinterval.NewTimeOfDay(17, 30, 0, 0))
+	night := timeinterval.NewTimeOfDayInterval(timeinterval.NewTimeOfDay(22, 0, 0, 0), timeinterval.NewTimeOfDay(6, 0, 0, 0))
+
+	// DST starts on 2024-03-10 in New York
+	di := timeinterval.NewDateInterval(timeinterval.NewDate(2024, 3, 9), timeinterval.NewDate(2024, 3, 11))
+
+	tis := office.TimeIntervals(di, newYork)
+	assert.Equal(t, len(tis.Elements()), 3)
+	assert.Equal(t, tis.Elements()[0].Start().Equal(timeinterval.NewTimePoint(2024, 3, 9, 14, 0, 0, 0)), true)
+	assert.Equal(t, tis.Elements()[2].Start().Equal(timeinterval.NewTimePoint(2024, 3, 11, 13, 0, 0, 0)), true)
+	assert.Equal(t, tis.Duration(), (time.Hour*8+time.Minute*30)*3)
+
+	tis = night.TimeIntervals(di, newYork)
+	assert.Equal(t, len(tis.Elements()), 3)
+	assert.Equal(t, tis.Elements()[0].Duration(), time.Hour*7)
+	assert.Equal(t, tis.Elements()[1].Duration(), time.Hour*8)
+	assert.Equal(t, tis.Elements()[2].End().Equal(timeinterval.NewTimePoint(2024, 3, 12, 10, 0, 0, 0)), true)
+}
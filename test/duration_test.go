@@ -0,0 +1,109 @@
+package timeinterval_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestWideDurationNew(t *testing.T) {
+	wd := timeinterval.NewWideDuration(1, 2_500_000_000)
+	assert.Equal(t, wd.Seconds(), int64(3))
+	assert.Equal(t, wd.Nanoseconds(), int64(500_000_000))
+
+	wd = timeinterval.NewWideDuration(0, -1)
+	assert.Equal(t, wd.Seconds(), int64(-1))
+	assert.Equal(t, wd.Nanoseconds(), int64(999_999_999))
+
+	wd = timeinterval.WideDurationOf(time.Minute + time.Millisecond)
+	assert.Equal(t, wd.Seconds(), int64(60))
+	assert.Equal(t, wd.Nanoseconds(), int64(1_000_000))
+
+	d, ok := wd.Duration()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, d, time.Minute+time.Millisecond)
+
+	assert.Equal(t, wd.Add(wd).Compare(timeinterval.WideDurationOf(time.Minute*2+time.Millisecond*2)), timeinterval.Equal)
+	assert.Equal(t, wd.Compare(wd.Add(timeinterval.NewWideDuration(0, 1))), timeinterval.Before)
+	assert.Equal(t, timeinterval.WideDurationOf(0).IsZero(), true)
+
+	assert.Panics(t, func() { timeinterval.NewWideDuration(math.MaxInt64, 1_000_000_000) })
+}
+
+func TestWideDurationRange(t *testing.T) {
+	d, ok := timeinterval.WideDurationOf(math.MaxInt64).Duration()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, d, time.Duration(math.MaxInt64))
+
+	d, ok = timeinterval.WideDurationOf(math.MinInt64).Duration()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, d, time.Duration(math.MinInt64))
+
+	over := timeinterval.WideDurationOf(math.MaxInt64).Add(timeinterval.NewWideDuration(0, 1))
+	_, ok = over.Duration()
+	assert.Equal(t, ok, false)
+	assert.Equal(t, over.Saturated(), time.Duration(math.MaxInt64))
+
+	under := timeinterval.WideDurationOf(math.MinInt64).Add(timeinterval.NewWideDuration(0, -1))
+	_, ok = under.Duration()
+	assert.Equal(t, ok, false)
+	assert.Equal(t, under.Saturated(), time.Duration(math.MinInt64))
+}
+
+func TestTimeIntervalWideDuration(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(1600, 1, 1, 0, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(2100, 1, 1, 0, 0, 0, 0)
+	t3 := timeinterval.NewTimePoint(2100, 1, 1, 0, 0, 0, 1)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+
+	// 400 years (146097 days) + 100 years (36525 days)
+	assert.Equal(t, ti12.WideDuration().Seconds(), int64(182622*24*60*60))
+	assert.Equal(t, ti12.WideDuration().Nanoseconds(), int64(0))
+	assert.Equal(t, ti13.WideDuration().Nanoseconds(), int64(1))
+	assert.Equal(t, t2.WideDiff(t1).Compare(t1.WideDiff(t2)), timeinterval.Equal)
+
+	assert.Equal(t, ti12.Duration(), time.Duration(math.MaxInt64))
+	_, err := ti12.DurationChecked()
+	assert.Equal(t, err, timeinterval.ErrDurationOverflow)
+
+	t4 := timeinterval.NewTimePoint(2000, 1, 1, 0, 0, 0, 0)
+	ti14 := timeinterval.NewTimeInterval(t1, t4)
+	ti42 := timeinterval.NewTimeInterval(t4, t2)
+
+	_, err = ti14.DurationChecked()
+	assert.Equal(t, err, timeinterval.ErrDurationOverflow)
+
+	d, err := ti42.DurationChecked()
+	assert.Nil(t, err)
+	assert.Equal(t, d, time.Hour*24*36525)
+}
+
+func TestTimeIntervalSetWideDuration(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(1800, 1, 1, 0, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(2000, 1, 1, 0, 0, 0, 0)
+	t3 := timeinterval.NewTimePoint(2200, 1, 1, 0, 0, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti23 := timeinterval.NewTimeInterval(t2, t3)
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(ti12, ti23)
+
+	// each element fits in time.Duration, but the sum does not
+	_, err := ti12.DurationChecked()
+	assert.Nil(t, err)
+	_, err = tis.DurationChecked()
+	assert.Equal(t, err, timeinterval.ErrDurationOverflow)
+
+	assert.Equal(t, tis.Duration(), time.Duration(math.MaxInt64))
+	assert.Equal(t, tis.WideDuration().Compare(timeinterval.NewTimeInterval(t1, t3).WideDuration()), timeinterval.Equal)
+
+	ptis := timeinterval.NewPersistentTimeIntervalSet(ti12, ti23)
+	assert.Equal(t, ptis.Duration(), time.Duration(math.MaxInt64))
+}
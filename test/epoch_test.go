@@ -0,0 +1,110 @@
+package timeinterval_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestTimePointFromTime(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+
+	tp := timeinterval.FromTime(time.Date(year, month, day+1, 4, 0, 0, 1, kst))
+	assert.Equal(t, tp.Equal(timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 1)), true)
+	assert.Equal(t, tp.Hour(), 19)
+
+	// ToTime() is always in UTC
+	assert.Equal(t, tp.ToTime(), time.Date(year, month, day, 19, 0, 0, 1, time.UTC))
+	assert.Equal(t, tp.ToTime().Location(), time.UTC)
+
+	// round trip
+	for _, v := range []time.Time{
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 999999999, kst),
+	} {
+		assert.Equal(t, timeinterval.FromTime(v).ToTime().Equal(v), true)
+		assert.Equal(t, timeinterval.FromTime(v.In(kst)).Equal(timeinterval.FromTime(v)), true)
+	}
+}
+
+func TestTimePointFromUnix(t *testing.T) {
+	const sec = 1707678000 // 2024-02-11T19:00:00Z
+
+	tp := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+
+	assert.Equal(t, timeinterval.FromUnix(sec, 0).Equal(tp), true)
+	assert.Equal(t, timeinterval.FromUnixMilli(sec*1000).Equal(tp), true)
+	assert.Equal(t, timeinterval.FromUnixMicro(sec*1000000).Equal(tp), true)
+	assert.Equal(t, timeinterval.FromUnixNano(sec*1000000000).Equal(tp), true)
+	assert.Equal(t, timeinterval.FromUnix(sec-1, 1000000000).Equal(tp), true)
+
+	assert.Equal(t, tp.Unix(), int64(sec))
+	assert.Equal(t, tp.UnixMilli(), int64(sec*1000))
+	assert.Equal(t, tp.UnixMicro(), int64(sec*1000000))
+	assert.Equal(t, tp.UnixNano(), int64(sec*1000000000))
+
+	// round trip
+	for _, v := range []int64{math.MinInt64, -1, 0, 1, sec*1000000000 + 123456789, math.MaxInt64} {
+		assert.Equal(t, timeinterval.FromUnixNano(v).UnixNano(), v)
+		assert.Equal(t, timeinterval.FromUnixMilli(v/1000000).UnixMilli(), v/1000000)
+		assert.Equal(t, timeinterval.FromUnix(v/1000000000, 0).Unix(), v/1000000000)
+	}
+
+	before := timeinterval.FromUnixNano(-1)
+	assert.Equal(t, before.Equal(timeinterval.NewTimePoint(1969, 12, 31, 23, 59, 59, 999999999)), true)
+	assert.Equal(t, before.Unix(), int64(-1))
+}
+
+func TestTimePointProtoTimestamp(t *testing.T) {
+	for _, v := range []time.Time{
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(year, month, day, 19, 0, 0, 1, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+	} {
+		ts := timestamppb.New(v)
+
+		tp := timeinterval.FromProtoTimestamp(ts)
+		assert.Equal(t, tp.ToTime(), ts.AsTime())
+
+		seconds, nanos := tp.ProtoTimestamp()
+		assert.Equal(t, seconds, ts.GetSeconds())
+		assert.Equal(t, nanos, ts.GetNanos())
+	}
+
+	assert.Panics(t, func() { timeinterval.FromProtoTimestamp(&timestamppb.Timestamp{Nanos: -1}) })
+	assert.Panics(t, func() { timeinterval.FromProtoTimestamp(nil) })
+}
+
+func TestWideDurationProtoDuration(t *testing.T) {
+	for _, v := range []time.Duration{
+		math.MinInt64, -time.Second * 3 / 2, -time.Second / 2, -1, 0, 1, time.Second / 2, time.Second * 3 / 2, math.MaxInt64,
+	} {
+		d := durationpb.New(v)
+
+		wd := timeinterval.FromProtoDuration(d)
+		got, ok := wd.Duration()
+		assert.Equal(t, ok, true)
+		assert.Equal(t, got, v)
+
+		seconds, nanos := wd.ProtoDuration()
+		assert.Equal(t, seconds, d.GetSeconds())
+		assert.Equal(t, nanos, d.GetNanos())
+	}
+
+	// longer than time.Duration
+	d := &durationpb.Duration{Seconds: 315576000000, Nanos: 1}
+	seconds, nanos := timeinterval.FromProtoDuration(d).ProtoDuration()
+	assert.Equal(t, seconds, d.GetSeconds())
+	assert.Equal(t, nanos, d.GetNanos())
+
+	assert.Panics(t, func() { timeinterval.FromProtoDuration(&durationpb.Duration{Seconds: 1, Nanos: -1}) })
+	assert.Panics(t, func() { timeinterval.FromProtoDuration(&durationpb.Duration{Nanos: 1000000000}) })
+}
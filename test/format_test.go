@@ -0,0 +1,135 @@
+package timeinterval_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestTimePointFormat(t *testing.T) {
+	tp := timeinterval.NewTimePoint(2024, 6, 3, 9, 0, 0, 0)
+
+	assert.Equal(t, tp.String(), "2024-06-03T09:00:00Z")
+	assert.Equal(t, fmt.Sprint(tp), "2024-06-03T09:00:00Z")
+	assert.Equal(t, fmt.Sprintf("%c", tp), "Mon 3 Jun, 09:00")
+	assert.Equal(t, fmt.Sprintf("%+v", tp), "Monday 3 June 2024, 09:00:00")
+	assert.Equal(t, fmt.Sprintf("%q", tp), `"2024-06-03T09:00:00Z"`)
+	assert.Equal(t, fmt.Sprintf("%22s|", tp), "  2024-06-03T09:00:00Z|")
+	assert.Equal(t, fmt.Sprintf("%d", tp), "%!d(*timeinterval.TimePoint=2024-06-03T09:00:00Z)")
+
+	tp = timeinterval.NewTimePoint(2024, 6, 3, 9, 0, 5, 250000000)
+	assert.Equal(t, tp.String(), "2024-06-03T09:00:05.25Z")
+	assert.Equal(t, fmt.Sprintf("%c", tp), "Mon 3 Jun, 09:00:05.25")
+	assert.Equal(t, timeinterval.LocaleGerman.FormatTimePoint(tp, timeinterval.FormatCompact), "Mo., 3. Juni, 09:00:05,25")
+
+	assert.Equal(t, timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 60, 0).String(), "2016-12-31T23:59:60Z")
+	assert.Equal(t, fmt.Sprintf("%c", timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 60, 0)), "Sat 31 Dec, 23:59:60")
+
+	var nilTP *timeinterval.TimePoint
+	assert.Equal(t, fmt.Sprint(nilTP), "<nil>")
+}
+
+func TestTimeIntervalFormat(t *testing.T) {
+	ti := timeinterval.NewTimeInterval(
+		timeinterval.NewTimePoint(2024, 6, 3, 9, 0, 0, 0),
+		timeinterval.NewTimePoint(2024, 6, 3, 10, 30, 0, 0),
+	)
+
+	assert.Equal(t, ti.String(), "2024-06-03T09:00:00Z/2024-06-03T10:30:00Z")
+	assert.Equal(t, fmt.Sprintf("%c", ti), "Mon 3 Jun, 09:00–10:30")
+	assert.Equal(t, fmt.Sprintf("%+v", ti), "Monday 3 June 2024, 09:00:00–10:30:00")
+
+	ti2 := timeinterval.NewTimeInterval(
+		timeinterval.NewTimePoint(2024, 6, 3, 22, 0, 0, 0),
+		timeinterval.NewTimePoint(2024, 6, 4, 1, 0, 0, 0),
+	)
+	assert.Equal(t, fmt.Sprintf("%c", ti2), "Mon 3 Jun, 22:00 – Tue 4 Jun, 01:00")
+	assert.Equal(t, fmt.Sprintf("%+v", ti2), "Monday 3 June, 22:00:00 – Tuesday 4 June 2024, 01:00:00")
+
+	ti3 := timeinterval.NewTimeInterval(
+		timeinterval.NewTimePoint(2024, 12, 31, 22, 0, 0, 0),
+		timeinterval.NewTimePoint(2025, 1, 1, 1, 0, 0, 0),
+	)
+	assert.Equal(t, fmt.Sprintf("%c", ti3), "Tue 31 Dec 2024, 22:00 – Wed 1 Jan 2025, 01:00")
+	assert.Equal(t, fmt.Sprintf("%+v", ti3), "Tuesday 31 December 2024, 22:00:00 – Wednesday 1 January 2025, 01:00:00")
+
+	testCases := []struct {
+		locale  *timeinterval.Locale
+		compact string
+		verbose string
+	}{
+		{timeinterval.LocaleGerman, "Mo., 3. Juni, 22:00 – Di., 4. Juni, 01:00", "Montag, 3. Juni, 22:00:00 – Dienstag, 4. Juni 2024, 01:00:00"},
+		{timeinterval.LocaleFrench, "lun. 3 juin, 22:00 – mar. 4 juin, 01:00", "lundi 3 juin, 22:00:00 – mardi 4 juin 2024, 01:00:00"},
+		{timeinterval.LocaleSpanish, "lun 3 jun, 22:00 – mar 4 jun, 01:00", "lunes, 3 de junio, 22:00:00 – martes, 4 de junio de 2024, 01:00:00"},
+		{timeinterval.LocaleKorean, "6월 3일 (월) 22:00 ~ 6월 4일 (화) 01:00", "2024년 6월 3일 월요일 22:00:00 ~ 6월 4일 화요일 01:00:00"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.locale.FormatTimeInterval(ti2, timeinterval.FormatCompact), tc.compact)
+		assert.Equal(t, tc.locale.FormatTimeInterval(ti2, timeinterval.FormatVerbose), tc.verbose)
+		assert.Equal(t, tc.locale.FormatTimeInterval(ti2, timeinterval.FormatISO), ti2.String())
+	}
+
+	assert.PanicsWithValue(t, "end is before start: start: 2024-06-03T10:30:00Z, end: 2024-06-03T09:00:00Z", func() {
+		timeinterval.NewTimeInterval(ti.End(), ti.Start())
+	})
+	assert.PanicsWithValue(t, "not mergeable: 2024-06-03T09:00:00Z/2024-06-03T10:30:00Z, 2024-06-03T22:00:00Z/2024-06-04T01:00:00Z", func() {
+		ti.Merge(ti2)
+	})
+}
+
+func TestTimeIntervalSetFormat(t *testing.T) {
+	tis := timeinterval.NewTimeIntervalSet()
+	assert.Equal(t, tis.String(), "[]")
+
+	tis.Add(
+		timeinterval.NewTimeInterval(
+			timeinterval.NewTimePoint(2024, 6, 3, 9, 0, 0, 0),
+			timeinterval.NewTimePoint(2024, 6, 3, 10, 30, 0, 0),
+		),
+		timeinterval.NewTimeInterval(
+			timeinterval.NewTimePoint(2024, 6, 4, 9, 0, 0, 0),
+			timeinterval.NewTimePoint(2024, 6, 4, 10, 0, 0, 0),
+		),
+	)
+
+	assert.Equal(t, tis.String(), "[2024-06-03T09:00:00Z/2024-06-03T10:30:00Z, 2024-06-04T09:00:00Z/2024-06-04T10:00:00Z]")
+	assert.Equal(t, fmt.Sprintf("%c", tis), "[Mon 3 Jun, 09:00–10:30; Tue 4 Jun, 09:00–10:00]")
+}
+
+func TestFormatDuration(t *testing.T) {
+	testCases := []struct {
+		d       time.Duration
+		iso     string
+		compact string
+		verbose string
+	}{
+		{0, "PT0S", "0 s", "0 seconds"},
+		{time.Hour*2 + time.Minute*15, "PT2H15M", "2 h 15 min", "2 hours 15 minutes"},
+		{time.Hour*26 + time.Second, "PT26H1S", "1 d 2 h 1 s", "1 day 2 hours 1 second"},
+		{time.Millisecond * 1500, "PT1.5S", "1.5 s", "1.5 seconds"},
+		{-time.Minute, "-PT1M", "-1 min", "-1 minute"},
+	}
+
+	l := timeinterval.LocaleEnglish
+	for _, tc := range testCases {
+		assert.Equal(t, l.FormatDuration(tc.d, timeinterval.FormatISO), tc.iso)
+		assert.Equal(t, l.FormatDuration(tc.d, timeinterval.FormatCompact), tc.compact)
+		assert.Equal(t, l.FormatDuration(tc.d, timeinterval.FormatVerbose), tc.verbose)
+	}
+
+	d := time.Hour*2 + time.Minute*15 + time.Millisecond*500
+	assert.Equal(t, timeinterval.LocaleGerman.FormatDuration(d, timeinterval.FormatVerbose), "2 Stunden 15 Minuten 0,5 Sekunden")
+	assert.Equal(t, timeinterval.LocaleKorean.FormatDuration(d, timeinterval.FormatCompact), "2시간 15분 0.5초")
+
+	assert.NotPanics(t, func() {
+		l.FormatDuration(time.Duration(-1<<63), timeinterval.FormatCompact)
+	})
+	assert.Panics(t, func() {
+		l.FormatDuration(0, timeinterval.FormatStyle(-1))
+	})
+}
@@ -0,0 +1,141 @@
+package timeinterval_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestTemporalJoin(t *testing.T) {
+	at := func(hour int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, day, hour, 0, 0, 0)
+	}
+
+	deployments := []timeinterval.LabeledTimeInterval[string]{
+		{Interval: timeinterval.NewTimeInterval(at(0), at(6)), Label: "v1"},
+		{Interval: timeinterval.NewTimeInterval(at(6), at(12)), Label: "v2"},
+		{Interval: timeinterval.NewTimeInterval(at(12), at(24)), Label: "v3"},
+	}
+
+	incidents := []timeinterval.LabeledTimeInterval[int]{
+		{Interval: timeinterval.NewTimeInterval(at(1), at(2)), Label: 1},
+		{Interval: timeinterval.NewTimeInterval(at(5), at(13)), Label: 2},
+		{Interval: timeinterval.NewTimeInterval(at(6), at(6)), Label: 3},
+		{Interval: timeinterval.NewTimeInterval(at(7), at(8)), Label: 4},
+	}
+
+	type pair struct {
+		left     int
+		right    string
+		interval string
+	}
+	summarize := func(pairs []timeinterval.JoinPair[int, string]) []pair {
+		ret := []pair{}
+		for _, v := range pairs {
+			p := pair{left: v.Left.Label}
+			if v.Right != nil {
+				p.right = v.Right.Label
+				p.interval = v.Interval.String()
+			} else {
+				assert.Nil(t, v.Interval)
+			}
+			ret = append(ret, p)
+		}
+		return ret
+	}
+
+	assert.Equal(t, summarize(timeinterval.TemporalJoin(incidents, deployments, timeinterval.InnerJoin)), []pair{
+		{1, "v1", "2024-02-11T01:00:00Z/2024-02-11T02:00:00Z"},
+		{2, "v1", "2024-02-11T05:00:00Z/2024-02-11T06:00:00Z"},
+		{2, "v2", "2024-02-11T06:00:00Z/2024-02-11T12:00:00Z"},
+		{2, "v3", "2024-02-11T12:00:00Z/2024-02-11T13:00:00Z"},
+		{4, "v2", "2024-02-11T07:00:00Z/2024-02-11T08:00:00Z"},
+	})
+
+	assert.Equal(t, summarize(timeinterval.TemporalJoin(incidents, deployments, timeinterval.LeftJoin)), []pair{
+		{1, "v1", "2024-02-11T01:00:00Z/2024-02-11T02:00:00Z"},
+		{2, "v1", "2024-02-11T05:00:00Z/2024-02-11T06:00:00Z"},
+		{2, "v2", "2024-02-11T06:00:00Z/2024-02-11T12:00:00Z"},
+		{2, "v3", "2024-02-11T12:00:00Z/2024-02-11T13:00:00Z"},
+		{3, "", ""},
+		{4, "v2", "2024-02-11T07:00:00Z/2024-02-11T08:00:00Z"},
+	})
+
+	assert.Equal(t, summarize(timeinterval.TemporalJoin(incidents, deployments, timeinterval.AntiJoin)), []pair{
+		{3, "", ""},
+	})
+
+	assert.Equal(t, len(timeinterval.TemporalJoin(incidents, deployments[:0], timeinterval.LeftJoin)), len(incidents))
+
+	// the empty elements overlap nothing, wherever they are
+	withEmpty := append([]timeinterval.LabeledTimeInterval[int]{incidents[0], {Interval: timeinterval.NewEmptyTimeInterval(), Label: 5}}, incidents[1:]...)
+	assert.Equal(t, summarize(timeinterval.TemporalJoin(withEmpty, deployments, timeinterval.AntiJoin)), []pair{
+		{5, "", ""},
+		{3, "", ""},
+	})
+
+	assert.Panics(t, func() {
+		timeinterval.TemporalJoin(deployments[1:2], []timeinterval.LabeledTimeInterval[int]{incidents[1], incidents[0]}, timeinterval.InnerJoin)
+	})
+	assert.Panics(t, func() {
+		timeinterval.TemporalJoin(deployments, incidents, timeinterval.JoinType(-1))
+	})
+}
+
+func TestTemporalJoinRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	random := func(n int) []timeinterval.LabeledTimeInterval[int] {
+		ret := []timeinterval.LabeledTimeInterval[int]{}
+		start := 0
+		for i := 0; i < n; i++ {
+			start += rnd.Intn(3)
+			ret = append(ret, timeinterval.LabeledTimeInterval[int]{
+				Interval: timeinterval.NewTimeInterval(
+					timeinterval.NewTimePoint(year, month, day, 0, start, 0, 0),
+					timeinterval.NewTimePoint(year, month, day, 0, start+rnd.Intn(10), 0, 0),
+				),
+				Label: i,
+			})
+		}
+		return ret
+	}
+
+	for n := 0; n < 200; n++ {
+		left, right := random(rnd.Intn(30)), random(rnd.Intn(30))
+
+		got := timeinterval.TemporalJoin(left, right, timeinterval.LeftJoin)
+
+		// nested loops
+		k := 0
+		for _, l := range left {
+			matched := false
+			for _, r := range right {
+				if !l.Interval.Intersects(r.Interval) {
+					continue
+				}
+				matched = true
+
+				if assert.Less(t, k, len(got)) {
+					assert.Equal(t, got[k].Left.Label, l.Label)
+					assert.Equal(t, got[k].Right.Label, r.Label)
+					assert.Equal(t, got[k].Interval, l.Interval.Intersection(r.Interval))
+					assert.Equal(t, got[k].Interval.IsEmpty(), false)
+				}
+				k++
+			}
+
+			if !matched {
+				if assert.Less(t, k, len(got)) {
+					assert.Equal(t, got[k].Left.Label, l.Label)
+					assert.Nil(t, got[k].Right)
+				}
+				k++
+			}
+		}
+		assert.Equal(t, len(got), k)
+	}
+}
@@ -0,0 +1,139 @@
+package timeinterval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestTimePointUTCLeapSecond(t *testing.T) {
+	t59 := timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 59, 0)
+	t60 := timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 60, 0)
+	t60half := timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 60, 500000000)
+	t00 := timeinterval.NewTimePointUTC(2017, 1, 1, 0, 0, 0, 0)
+
+	assert.Equal(t, t60.IsLeapSecond(), true)
+	assert.Equal(t, t60.Scale(), timeinterval.TimeScaleUTC)
+	assert.Equal(t, t60.Year(), 2016)
+	assert.Equal(t, t60.Month(), 12)
+	assert.Equal(t, t60.Day(), 31)
+	assert.Equal(t, t60.Hour(), 23)
+	assert.Equal(t, t60.Minute(), 59)
+	assert.Equal(t, t60.Second(), 60)
+	assert.Equal(t, t60half.Nanosecond(), 500000000)
+
+	assert.Equal(t, t59.Before(t60), true)
+	assert.Equal(t, t60.Before(t60half), true)
+	assert.Equal(t, t60half.Before(t00), true)
+	assert.Equal(t, t00.After(t60half), true)
+	assert.Equal(t, t60.Equal(timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 60, 0)), true)
+	assert.Equal(t, t60.Equal(t00), false)
+
+	// POSIX TimePoints are also comparable to leap seconds
+	assert.Equal(t, timeinterval.NewTimePoint(2017, 1, 1, 0, 0, 0, 0).After(t60half), true)
+
+	assert.Equal(t, t59.Diff(t00), time.Second*2)
+	assert.Equal(t, t59.Diff(t60), time.Second)
+	assert.Equal(t, t60half.Diff(t00), time.Millisecond*500)
+	assert.Equal(t, t00.Diff(t59), time.Second*2)
+
+	// without leap seconds
+	assert.Equal(t, timeinterval.NewTimePoint(2016, 12, 31, 23, 59, 59, 0).Diff(timeinterval.NewTimePoint(2017, 1, 1, 0, 0, 0, 0)), time.Second)
+	assert.Equal(t, timeinterval.NewTimePoint(2016, 12, 31, 23, 59, 60, 0).Equal(timeinterval.NewTimePoint(2017, 1, 1, 0, 0, 0, 0)), true)
+
+	// no leap second on this day, normalized as NewTimePoint
+	tp := timeinterval.NewTimePointUTC(2017, 12, 31, 23, 59, 60, 0)
+	assert.Equal(t, tp.IsLeapSecond(), false)
+	assert.Equal(t, tp.Equal(timeinterval.NewTimePoint(2018, 1, 1, 0, 0, 0, 0)), true)
+}
+
+func TestTimePointUTCLeapSecondTable(t *testing.T) {
+	days := [][2]int{
+		{1972, 6}, {1972, 12}, {1973, 12}, {1974, 12}, {1975, 12}, {1976, 12}, {1977, 12}, {1978, 12}, {1979, 12},
+		{1981, 6}, {1982, 6}, {1983, 6}, {1985, 6}, {1987, 12}, {1989, 12}, {1990, 12},
+		{1992, 6}, {1993, 6}, {1994, 6}, {1995, 12}, {1997, 6}, {1998, 12},
+		{2005, 12}, {2008, 12}, {2012, 6}, {2015, 6}, {2016, 12},
+	}
+
+	for i, v := range days {
+		lastDay := 31
+		if v[1] == 6 {
+			lastDay = 30
+		}
+
+		tp := timeinterval.NewTimePointUTC(v[0], v[1], lastDay, 23, 59, 60, 0)
+		assert.Equal(t, tp.IsLeapSecond(), true, v)
+
+		// TAI - UTC is 10s in 1972-01-01 and increases by 1s for every leap second
+		next := timeinterval.NewTimePointUTC(v[0], v[1]+1, 1, 0, 0, 0, 0)
+		assert.Equal(t, next.TAI().Sub(next.ToTime()), time.Second*time.Duration(11+i), v)
+	}
+
+	assert.Equal(t, timeinterval.NewTimePointUTC(1972, 1, 1, 0, 0, 0, 0).TAI(), time.Date(1972, 1, 1, 0, 0, 10, 0, time.UTC))
+}
+
+func TestTimeIntervalUTCDuration(t *testing.T) {
+	start := timeinterval.NewTimePointUTC(1972, 1, 1, 0, 0, 0, 0)
+	end := timeinterval.NewTimePointUTC(2017, 1, 1, 0, 0, 0, 0)
+
+	ti := timeinterval.NewTimeInterval(start, end)
+	posix := timeinterval.NewTimeInterval(timeinterval.FromTime(start.ToTime()), timeinterval.FromTime(end.ToTime()))
+
+	assert.Equal(t, ti.Duration()-posix.Duration(), time.Second*27)
+	assert.Equal(t, ti.WideDuration().Seconds()-posix.WideDuration().Seconds(), int64(27))
+
+	leap := timeinterval.NewTimeInterval(
+		timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 60, 0),
+		timeinterval.NewTimePointUTC(2017, 1, 1, 0, 0, 0, 0),
+	)
+	assert.Equal(t, leap.Duration(), time.Second)
+	assert.Equal(t, leap.IsZeroDuration(), false)
+
+	// the leap second is not mergeable with the following second
+	before := timeinterval.NewTimeInterval(
+		timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 0, 0),
+		timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 60, 0),
+	)
+	after := timeinterval.NewTimeInterval(
+		timeinterval.NewTimePointUTC(2017, 1, 1, 0, 0, 0, 0),
+		timeinterval.NewTimePointUTC(2017, 1, 1, 0, 1, 0, 0),
+	)
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(after, before)
+	tis.Cleanup(true)
+	assert.Equal(t, len(tis.Elements()), 2)
+	assert.Equal(t, tis.Duration(), time.Minute*2)
+
+	tis.Add(leap)
+	tis.Cleanup(true)
+	assert.Equal(t, len(tis.Elements()), 1)
+	assert.Equal(t, tis.Duration(), time.Minute*2+time.Second)
+}
+
+func TestTimePointFromTAI(t *testing.T) {
+	// 2016-12-31T23:59:60Z is 2017-01-01T00:00:36 TAI
+	tp := timeinterval.FromTAI(time.Date(2017, 1, 1, 0, 0, 36, 250, time.UTC))
+	assert.Equal(t, tp.IsLeapSecond(), true)
+	assert.Equal(t, tp.Equal(timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 60, 250)), true)
+
+	tp = timeinterval.FromTAI(time.Date(2017, 1, 1, 0, 0, 37, 0, time.UTC))
+	assert.Equal(t, tp.IsLeapSecond(), false)
+	assert.Equal(t, tp.Equal(timeinterval.NewTimePoint(2017, 1, 1, 0, 0, 0, 0)), true)
+
+	tp = timeinterval.FromTAI(time.Date(2017, 1, 1, 0, 0, 35, 999999999, time.UTC))
+	assert.Equal(t, tp.Equal(timeinterval.NewTimePoint(2016, 12, 31, 23, 59, 59, 999999999)), true)
+
+	// round trip every second around a leap second
+	for tp := timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 50, 0); tp.Before(timeinterval.NewTimePointUTC(2017, 1, 1, 0, 0, 10, 0)); {
+		assert.Equal(t, timeinterval.FromTAI(tp.TAI()).Equal(tp), true, tp.TAI())
+
+		next := timeinterval.FromTAI(tp.TAI().Add(time.Second))
+		assert.Equal(t, tp.Diff(next), time.Second)
+		tp = next
+	}
+
+	assert.Equal(t, timeinterval.FromTAI(time.Date(1960, 1, 1, 0, 0, 10, 0, time.UTC)).Equal(timeinterval.NewTimePoint(1960, 1, 1, 0, 0, 0, 0)), true)
+}
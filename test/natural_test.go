@@ -0,0 +1,156 @@
+package timeinterval_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestParseNatural(t *testing.T) {
+	// Wednesday
+	ref := timeinterval.NewTimePoint(2024, 6, 5, 10, 30, 0, 0)
+
+	testCases := []struct {
+		input string
+		want  []string
+	}{
+		{"today", []string{"2024-06-05T00:00:00Z/2024-06-06T00:00:00Z"}},
+		{"yesterday", []string{"2024-06-04T00:00:00Z/2024-06-05T00:00:00Z"}},
+		{"tomorrow 2-4pm", []string{"2024-06-06T14:00:00Z/2024-06-06T16:00:00Z"}},
+		{"2-4pm tomorrow", []string{"2024-06-06T14:00:00Z/2024-06-06T16:00:00Z"}},
+		{"TOMORROW 2 – 4 P.M.", []string{"2024-06-06T14:00:00Z/2024-06-06T16:00:00Z"}},
+		{"tomorrow at 3pm", []string{"2024-06-06T15:00:00Z/2024-06-06T15:00:00Z"}},
+		{"tomorrow 10pm-2am", []string{"2024-06-06T22:00:00Z/2024-06-07T02:00:00Z"}},
+		{"tomorrow 12-1pm", []string{"2024-06-06T12:00:00Z/2024-06-06T13:00:00Z"}},
+		{"today 11-1pm", []string{"2024-06-05T11:00:00Z/2024-06-05T13:00:00Z"}},
+		{"today 10am-2", []string{"2024-06-05T10:00:00Z/2024-06-05T14:00:00Z"}},
+		{"today 9 to 17", []string{"2024-06-05T09:00:00Z/2024-06-05T17:00:00Z"}},
+		{"today 14:30-15:15", []string{"2024-06-05T14:30:00Z/2024-06-05T15:15:00Z"}},
+		{"today midnight-6am", []string{"2024-06-05T00:00:00Z/2024-06-05T06:00:00Z"}},
+		{"between 10am and noon", []string{"2024-06-05T10:00:00Z/2024-06-05T12:00:00Z"}},
+		{"noon to 2", []string{"2024-06-05T12:00:00Z/2024-06-05T14:00:00Z"}},
+		{"12:30 to 1", []string{"2024-06-05T12:30:00Z/2024-06-05T13:00:00Z"}},
+		{"20:00 to 2", []string{"2024-06-05T20:00:00Z/2024-06-06T02:00:00Z"}},
+		{"11 to 13:30", []string{"2024-06-05T11:00:00Z/2024-06-05T13:30:00Z"}},
+		{"11 to noon", []string{"2024-06-05T11:00:00Z/2024-06-05T12:00:00Z"}},
+		{"10pm to midnight", []string{"2024-06-05T22:00:00Z/2024-06-06T00:00:00Z"}},
+		{"midnight to 2", []string{"2024-06-05T00:00:00Z/2024-06-05T02:00:00Z"}},
+		{"9 to 5", []string{"2024-06-05T09:00:00Z/2024-06-05T17:00:00Z"}},
+		{"on Friday from 9am until 11:30", []string{"2024-06-07T09:00:00Z/2024-06-07T11:30:00Z"}},
+		{"tomorrow morning", []string{"2024-06-06T06:00:00Z/2024-06-06T12:00:00Z"}},
+		{"tomorrow night", []string{"2024-06-06T22:00:00Z/2024-06-07T06:00:00Z"}},
+		{"2024-06-20 09:00-17:00", []string{"2024-06-20T09:00:00Z/2024-06-20T17:00:00Z"}},
+		{"Wednesday", []string{"2024-06-05T00:00:00Z/2024-06-06T00:00:00Z"}},
+		{"monday", []string{"2024-06-10T00:00:00Z/2024-06-11T00:00:00Z"}},
+		{"this Monday", []string{"2024-06-03T00:00:00Z/2024-06-04T00:00:00Z"}},
+		{"last Friday", []string{"2024-05-31T00:00:00Z/2024-06-01T00:00:00Z"}},
+		{"next Sunday", []string{"2024-06-16T00:00:00Z/2024-06-17T00:00:00Z"}},
+		{"Mon-Fri", []string{"2024-06-10T00:00:00Z/2024-06-15T00:00:00Z"}},
+		{"Friday to Monday", []string{"2024-06-07T00:00:00Z/2024-06-11T00:00:00Z"}},
+		{"last week", []string{"2024-05-27T00:00:00Z/2024-06-03T00:00:00Z"}},
+		{"this week", []string{"2024-06-03T00:00:00Z/2024-06-10T00:00:00Z"}},
+		{"next week", []string{"2024-06-10T00:00:00Z/2024-06-17T00:00:00Z"}},
+		{"this weekend", []string{"2024-06-08T00:00:00Z/2024-06-10T00:00:00Z"}},
+		{"last month", []string{"2024-05-01T00:00:00Z/2024-06-01T00:00:00Z"}},
+		{"next year", []string{"2025-01-01T00:00:00Z/2026-01-01T00:00:00Z"}},
+		{"last 3 days", []string{"2024-06-02T00:00:00Z/2024-06-05T00:00:00Z"}},
+		{"past 2 weeks", []string{"2024-05-20T00:00:00Z/2024-06-03T00:00:00Z"}},
+		{"next 2 weeks", []string{"2024-06-10T00:00:00Z/2024-06-24T00:00:00Z"}},
+		{"last 2 hours", []string{"2024-06-05T08:30:00Z/2024-06-05T10:30:00Z"}},
+		{"next 30 minutes", []string{"2024-06-05T10:30:00Z/2024-06-05T11:00:00Z"}},
+		{"next Monday through Friday 9 to 5", []string{
+			"2024-06-10T09:00:00Z/2024-06-10T17:00:00Z",
+			"2024-06-11T09:00:00Z/2024-06-11T17:00:00Z",
+			"2024-06-12T09:00:00Z/2024-06-12T17:00:00Z",
+			"2024-06-13T09:00:00Z/2024-06-13T17:00:00Z",
+			"2024-06-14T09:00:00Z/2024-06-14T17:00:00Z",
+		}},
+		{"this weekend at noon", []string{
+			"2024-06-08T12:00:00Z/2024-06-08T12:00:00Z",
+			"2024-06-09T12:00:00Z/2024-06-09T12:00:00Z",
+		}},
+	}
+
+	for _, tc := range testCases {
+		tis, err := timeinterval.ParseNatural(tc.input, ref, time.UTC)
+		if !assert.Nil(t, err, tc.input) {
+			continue
+		}
+
+		got := []string{}
+		for _, v := range tis.Elements() {
+			got = append(got, v.String())
+		}
+		assert.Equal(t, got, tc.want, tc.input)
+	}
+}
+
+func TestParseNaturalError(t *testing.T) {
+	ref := timeinterval.NewTimePoint(2024, 6, 5, 10, 30, 0, 0)
+
+	testCases := []struct {
+		input     string
+		offset    int
+		ambiguous bool
+	}{
+		{"", 0, false},
+		{"tomorrow 2-4", 9, true},
+		{"tomorrow 3", 9, true},
+		{"9-11 today", 0, true},
+		{"next fortnight", 5, false},
+		{"Friday through last Monday", 15, false},
+		{"tomorrow 25:00", 9, false},
+		{"tomorrow 13pm", 9, false},
+		{"last 0 days", 5, false},
+		{"last 3 fortnights", 7, false},
+		{"tomorrow yesterday", 9, false},
+		{"2024-02-30", 0, false},
+		{"this 3 days", 5, false},
+		{"between 10am", 12, false},
+		{"tomorrow at", 11, false},
+	}
+
+	for _, tc := range testCases {
+		_, err := timeinterval.ParseNatural(tc.input, ref, time.UTC)
+
+		var parseErr *timeinterval.NaturalParseError
+		if !assert.Equal(t, errors.As(err, &parseErr), true, tc.input) {
+			continue
+		}
+
+		assert.Equal(t, parseErr.Input, tc.input)
+		assert.Equal(t, parseErr.Offset, tc.offset, tc.input)
+		assert.Equal(t, errors.Is(err, timeinterval.ErrAmbiguous), tc.ambiguous, tc.input)
+	}
+}
+
+func TestParseNaturalInterval(t *testing.T) {
+	ref := timeinterval.NewTimePoint(2024, 6, 5, 10, 30, 0, 0)
+
+	ti, err := timeinterval.ParseNaturalInterval("last week", ref, time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, ti.Duration(), time.Hour*24*7)
+
+	_, err = timeinterval.ParseNaturalInterval("Mon-Fri 9 to 5", ref, time.UTC)
+	assert.NotNil(t, err)
+
+	// DST starts on 2024-03-10 in New York
+	loc, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+
+	ref = timeinterval.NewTimePoint(2024, 3, 9, 17, 0, 0, 0)
+	ti, err = timeinterval.ParseNaturalInterval("tomorrow", ref, loc)
+	assert.Nil(t, err)
+	assert.Equal(t, ti.String(), "2024-03-10T05:00:00Z/2024-03-11T04:00:00Z")
+	assert.Equal(t, ti.Duration(), time.Hour*23)
+
+	// 02:00 is 15:00 of the previous day in UTC
+	ref = timeinterval.NewTimePoint(2024, 3, 10, 2, 0, 0, 0)
+	ti, err = timeinterval.ParseNaturalInterval("today 9am", ref, loc)
+	assert.Nil(t, err)
+	assert.Equal(t, ti.String(), "2024-03-09T14:00:00Z/2024-03-09T14:00:00Z")
+}
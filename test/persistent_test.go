@@ -0,0 +1,113 @@
+package timeinterval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestPersistentTimeIntervalSetAdd(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti34 := timeinterval.NewTimeInterval(t3, t4)
+
+	v0 := timeinterval.NewPersistentTimeIntervalSet()
+	v1 := v0.Add(ti34)
+	v2 := v1.Add(ti13, ti12)
+	v3 := v2.Remove(ti13)
+
+	assert.Equal(t, v0.Len(), 0)
+	assert.Equal(t, v1.Len(), 1)
+	assert.Equal(t, v2.Len(), 3)
+	assert.Equal(t, v3.Len(), 2)
+
+	assert.Equal(t, v1.Duration(), time.Minute)
+	assert.Equal(t, v2.Duration(), time.Minute*4)
+	assert.Equal(t, v3.Duration(), time.Minute*2)
+
+	elements := v2.Elements()
+	assert.Equal(t, elements[0].Equal(ti12), true)
+	assert.Equal(t, elements[1].Equal(ti13), true)
+	assert.Equal(t, elements[2].Equal(ti34), true)
+
+	assert.Equal(t, v2.Contains(ti13), true)
+	assert.Equal(t, v3.Contains(ti13), false)
+	assert.Equal(t, v3.Remove(ti13), v3)
+
+	assert.Equal(t, v2.Clear().Len(), 0)
+	assert.Equal(t, v2.Len(), 3)
+}
+
+func TestPersistentTimeIntervalSetCleanup(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+	t5 := timeinterval.NewTimePoint(year, month, day, 19, 4, 0, 0)
+
+	ti11 := timeinterval.NewTimeInterval(t1, t1)
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti23 := timeinterval.NewTimeInterval(t2, t3)
+	ti45 := timeinterval.NewTimeInterval(t4, t5)
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+
+	before := timeinterval.NewPersistentTimeIntervalSet(ti45, ti11, ti23).
+		Merge(timeinterval.NewPersistentTimeIntervalSet(ti12))
+	after := before.Cleanup(true)
+
+	assert.Equal(t, before.Len(), 4)
+	assert.Equal(t, after.Len(), 2)
+	assert.Equal(t, after.Elements()[0].Equal(ti13), true)
+	assert.Equal(t, after.Elements()[1].Equal(ti45), true)
+
+	tis := after.TimeIntervalSet()
+	assert.Equal(t, len(tis.Elements()), 2)
+	assert.Equal(t, tis.Duration(), after.Duration())
+}
+
+func TestPersistentTimeIntervalSetMany(t *testing.T) {
+	tis := timeinterval.NewTimeIntervalSet()
+	ptis := timeinterval.NewPersistentTimeIntervalSet()
+	versions := []*timeinterval.PersistentTimeIntervalSet{}
+
+	// insert in a scrambled order
+	for i := 0; i < 1000; i++ {
+		m := (i * 7919) % 1000
+		ti := timeinterval.NewTimeInterval(
+			timeinterval.NewTimePoint(year, month, day, 0, m, 0, 0),
+			timeinterval.NewTimePoint(year, month, day, 0, m, i%60, 0),
+		)
+
+		tis.Add(ti)
+		ptis = ptis.Add(ti)
+		versions = append(versions, ptis)
+	}
+	tis.Sort()
+
+	assert.Equal(t, ptis.Len(), 1000)
+	assert.Equal(t, ptis.Duration(), tis.Duration())
+	for i, ti := range ptis.Elements() {
+		assert.Equal(t, ti.Equal(tis.Elements()[i]), true)
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		ptis = ptis.Remove(tis.Elements()[i])
+	}
+	assert.Equal(t, ptis.Len(), 500)
+	for i, ti := range ptis.Elements() {
+		assert.Equal(t, ti.Equal(tis.Elements()[i*2+1]), true)
+	}
+
+	// old versions are untouched
+	for i, v := range versions {
+		assert.Equal(t, v.Len(), i+1)
+	}
+}
@@ -0,0 +1,212 @@
+package timeinterval_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+// The properties are checked against a brute force reference,
+// which represents a set as the minutes of a grid it covers.
+
+const gridMinutes = 256 + 16
+
+type grid [gridMinutes]bool
+
+func gridTimePoint(min int) *timeinterval.TimePoint {
+	return timeinterval.NewTimePoint(year, month, day, 0, min, 0, 0)
+}
+
+func gridMinute(tp *timeinterval.TimePoint) int {
+	return int(tp.ToTime().Sub(gridTimePoint(0).ToTime()) / time.Minute)
+}
+
+// decodeSet decodes data as pairs of the start and the duration in minutes,
+// so the fuzzer can make any TimeIntervalSet on the grid, including zero duration elements
+func decodeSet(data []byte) *timeinterval.TimeIntervalSet {
+	ret := timeinterval.NewTimeIntervalSet()
+
+	for i := 0; i+1 < len(data); i += 2 {
+		start := int(data[i])
+		ret.Add(timeinterval.NewTimeInterval(gridTimePoint(start), gridTimePoint(start+int(data[i+1]%16))))
+	}
+
+	return ret
+}
+
+func randomSet(rnd *rand.Rand) *timeinterval.TimeIntervalSet {
+	data := make([]byte, rnd.Intn(20)*2)
+	rnd.Read(data)
+
+	// crowded sets, to have many overlaps
+	for i := 0; i < len(data); i += 2 {
+		data[i] %= 64
+	}
+
+	return decodeSet(data)
+}
+
+func referenceGrid(tis *timeinterval.TimeIntervalSet) grid {
+	ret := grid{}
+
+	for _, v := range tis.Elements() {
+		for m := gridMinute(v.Start()); m < gridMinute(v.End()); m++ {
+			ret[m] = true
+		}
+	}
+
+	return ret
+}
+
+func referenceOp(a, b grid, op func(x, y bool) bool) grid {
+	ret := grid{}
+
+	for i := range ret {
+		ret[i] = op(a[i], b[i])
+	}
+
+	return ret
+}
+
+func referenceDuration(g grid) time.Duration {
+	ret := time.Duration(0)
+
+	for _, v := range g {
+		if v {
+			ret += time.Minute
+		}
+	}
+
+	return ret
+}
+
+// assertNormalized checks tis is as cleaned up with removeZeroDuration:
+// sorted, no zero duration elements and no mergeable neighbors
+func assertNormalized(t *testing.T, tis *timeinterval.TimeIntervalSet) {
+	elements := tis.Elements()
+
+	for i, v := range elements {
+		assert.False(t, v.IsZeroDuration(), "zero duration: %v", tis)
+
+		if i > 0 {
+			assert.True(t, elements[i-1].End().Before(v.Start()), "not separated: %v", tis)
+		}
+	}
+}
+
+func assertSameSet(t *testing.T, got, want *timeinterval.TimeIntervalSet) {
+	if !assert.Equal(t, len(got.Elements()), len(want.Elements()), "%v != %v", got, want) {
+		return
+	}
+
+	for i, v := range got.Elements() {
+		assert.True(t, v.Equal(want.Elements()[i]), "%v != %v", got, want)
+	}
+}
+
+func checkAlgebra(t *testing.T, a, b *timeinterval.TimeIntervalSet) {
+	ga, gb := referenceGrid(a), referenceGrid(b)
+
+	union := a.Union(b)
+	intersect := a.Intersect(b)
+	subtract := a.Subtract(b)
+	gaps := a.Gaps()
+
+	// against the reference
+	assert.Equal(t, referenceGrid(union), referenceOp(ga, gb, func(x, y bool) bool { return x || y }))
+	assert.Equal(t, referenceGrid(intersect), referenceOp(ga, gb, func(x, y bool) bool { return x && y }))
+	assert.Equal(t, referenceGrid(subtract), referenceOp(ga, gb, func(x, y bool) bool { return x && !y }))
+
+	cleaned := a.Union()
+	if len(cleaned.Elements()) > 0 {
+		first := gridMinute(cleaned.Elements()[0].Start())
+		last := gridMinute(cleaned.Elements()[len(cleaned.Elements())-1].End())
+
+		want := grid{}
+		for i := first; i < last; i++ {
+			want[i] = !ga[i]
+		}
+
+		assert.Equal(t, referenceGrid(gaps), want)
+		assert.Equal(t, gaps.Duration(), time.Minute*time.Duration(last-first)-referenceDuration(ga))
+	} else {
+		assert.Equal(t, len(gaps.Elements()), 0)
+	}
+
+	for _, v := range []*timeinterval.TimeIntervalSet{union, intersect, subtract, gaps, cleaned} {
+		assertNormalized(t, v)
+	}
+
+	// union is commutative
+	assertSameSet(t, b.Union(a), union)
+	assertSameSet(t, b.Intersect(a), intersect)
+
+	// A - B ∪ A ∩ B = A
+	assertSameSet(t, subtract.Union(intersect), cleaned)
+
+	// Cleanup is idempotent
+	for _, removeZeroDuration := range []bool{false, true} {
+		once := a.Copy()
+		once.Cleanup(removeZeroDuration)
+		twice := once.Copy()
+		twice.Cleanup(removeZeroDuration)
+		assertSameSet(t, twice, once)
+	}
+
+	// Duration is additive after Cleanup
+	assert.Equal(t, cleaned.Duration(), referenceDuration(ga))
+	assert.Equal(t, union.Duration(), a.Union().Duration()+b.Union().Duration()-intersect.Duration())
+	assert.Equal(t, subtract.Duration()+intersect.Duration(), cleaned.Duration())
+}
+
+func TestTimeIntervalSetAlgebraProperties(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 500; n++ {
+		checkAlgebra(t, randomSet(rnd), randomSet(rnd))
+	}
+}
+
+func FuzzTimeIntervalSetAlgebra(f *testing.F) {
+	f.Add([]byte{}, []byte{})
+	f.Add([]byte{0, 5, 5, 5, 20, 0}, []byte{3, 4})
+	f.Add([]byte{10, 10, 12, 2, 30, 15}, []byte{10, 0, 20, 10, 29, 1})
+	f.Add([]byte{255, 15, 0, 15}, []byte{250, 15})
+
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		checkAlgebra(t, decodeSet(a), decodeSet(b))
+	})
+}
+
+func FuzzTimeIntervalSubtract(f *testing.F) {
+	f.Add(byte(0), byte(10), byte(5), byte(2))
+	f.Add(byte(0), byte(10), byte(0), byte(10))
+	f.Add(byte(5), byte(0), byte(0), byte(10))
+
+	f.Fuzz(func(t *testing.T, s1, d1, s2, d2 byte) {
+		a := decodeSet([]byte{s1, d1})
+		b := decodeSet([]byte{s2, d2})
+		ti, ti2 := a.Elements()[0], b.Elements()[0]
+
+		got := ti.Subtract(ti2)
+
+		// zero duration ti is kept unless it is strictly within ti2
+		if ti.IsZeroDuration() {
+			if ti2.Intersects(ti) {
+				assert.Equal(t, len(got.Elements()), 0)
+			} else {
+				assertSameSet(t, got, a)
+			}
+			return
+		}
+
+		assertNormalized(t, got)
+		assert.Equal(t, referenceGrid(got), referenceOp(referenceGrid(a), referenceGrid(b), func(x, y bool) bool { return x && !y }))
+
+		assert.Equal(t, ti.Intersection(ti2).Duration()+got.Duration(), ti.Duration())
+	})
+}
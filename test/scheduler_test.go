@@ -0,0 +1,133 @@
+package timeinterval_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestScheduler(t *testing.T) {
+	at := func(hour, min int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(2024, 6, 5, hour, min, 0, 0)
+	}
+	interval := func(h1, m1, h2, m2 int) *timeinterval.TimeInterval {
+		return timeinterval.NewTimeInterval(at(h1, m1), at(h2, m2))
+	}
+
+	fc := timeinterval.NewFakeClock(at(9, 0).ToTime())
+	s := timeinterval.NewScheduler(fc)
+
+	events := make(chan string, 100)
+	hooks := timeinterval.SchedulerHooks{
+		OnStart: func(ti *timeinterval.TimeInterval) { events <- "start " + ti.String() },
+		OnEnd:   func(ti *timeinterval.TimeInterval) { events <- "end " + ti.String() },
+	}
+
+	ti1 := interval(10, 0, 11, 0)
+	ti2 := interval(11, 0, 12, 0)
+	ti3 := interval(8, 0, 9, 30) // started
+	ti4 := interval(7, 0, 8, 0)  // ended
+	ti5 := interval(12, 0, 12, 0)
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(ti2, ti4, ti5)
+
+	bg := context.Background()
+	s.Add(bg, ti1, hooks)
+	s.Add(bg, ti3, hooks)
+	s.AddSet(bg, tis, hooks)
+	assert.Equal(t, s.Pending(), 8)
+
+	ctx, cancel := context.WithCancel(bg)
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx)
+	}()
+
+	assert.Equal(t, <-events, "start "+ti3.String())
+
+	fc.BlockUntil(1)
+	fc.Advance(time.Minute * 30)
+	assert.Equal(t, <-events, "end "+ti3.String())
+
+	fc.BlockUntil(1)
+	fc.Set(at(10, 0).ToTime())
+	assert.Equal(t, <-events, "start "+ti1.String())
+
+	// ends first
+	fc.BlockUntil(1)
+	fc.Set(at(11, 0).ToTime())
+	assert.Equal(t, <-events, "end "+ti1.String())
+	assert.Equal(t, <-events, "start "+ti2.String())
+
+	// all at once
+	fc.BlockUntil(1)
+	fc.Set(at(12, 30).ToTime())
+	assert.Equal(t, <-events, "end "+ti2.String())
+	assert.Equal(t, <-events, "start "+ti5.String())
+	assert.Equal(t, <-events, "end "+ti5.String())
+	assert.Equal(t, s.Pending(), 0)
+
+	// cancelling after OnStart
+	ctx6, cancel6 := context.WithCancel(bg)
+	ti6 := interval(13, 0, 14, 0)
+	s.Add(ctx6, ti6, hooks)
+
+	fc.BlockUntil(1)
+	fc.Set(at(13, 0).ToTime())
+	assert.Equal(t, <-events, "start "+ti6.String())
+
+	cancel6()
+	assert.Eventually(t, func() bool { return s.Pending() == 0 }, time.Second, time.Millisecond)
+
+	ti7 := interval(14, 0, 15, 0)
+	s.Add(bg, ti7, timeinterval.SchedulerHooks{
+		OnStart: hooks.OnStart,
+	})
+	s.Add(ctx6, interval(14, 0, 14, 30), hooks)
+	assert.Equal(t, s.Pending(), 2)
+
+	fc.BlockUntil(1)
+	fc.Set(at(15, 0).ToTime())
+	assert.Equal(t, <-events, "start "+ti7.String())
+
+	cancel()
+	assert.Equal(t, <-done, context.Canceled)
+	assert.Equal(t, len(events), 0)
+}
+
+func TestSchedulerCron(t *testing.T) {
+	fc := timeinterval.NewFakeClock(time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC))
+	s := timeinterval.NewScheduler(fc)
+
+	cs, err := timeinterval.ParseCron("0 2 * * *", time.UTC)
+	assert.Nil(t, err)
+
+	bound := timeinterval.NewTimeInterval(
+		timeinterval.FromTime(fc.Now()),
+		timeinterval.FromTime(fc.Now().Add(time.Hour*24*3)),
+	)
+
+	ends := make(chan *timeinterval.TimeInterval, 10)
+	s.AddSet(context.Background(), cs.TimeIntervals(bound, time.Hour), timeinterval.SchedulerHooks{
+		OnEnd: func(ti *timeinterval.TimeInterval) { ends <- ti },
+	})
+	assert.Equal(t, s.Pending(), 6)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	for i := 0; i < 3; i++ {
+		fc.BlockUntil(1)
+		fc.Advance(time.Hour * 24)
+
+		ti := <-ends
+		assert.Equal(t, ti.End().Hour(), 3)
+		assert.Equal(t, ti.End().Day(), 5+i)
+	}
+}
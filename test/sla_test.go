@@ -0,0 +1,93 @@
+package timeinterval_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestSLA(t *testing.T) {
+	at := func(d, hour, min int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, d, hour, min, 0, 0)
+	}
+
+	outages := timeinterval.NewTimeIntervalSet()
+	outages.Add(
+		timeinterval.NewTimeInterval(at(2, 1, 0), at(2, 2, 0)),
+		timeinterval.NewTimeInterval(at(3, 10, 0), at(3, 11, 0)),
+		timeinterval.NewTimeInterval(at(3, 10, 30), at(3, 11, 30)), // overlapping
+		timeinterval.NewTimeInterval(at(5, 3, 0), at(5, 5, 0)),     // during maintenance
+	)
+
+	maintenance := timeinterval.NewTimeIntervalSet()
+	maintenance.Add(timeinterval.NewTimeInterval(at(5, 2, 0), at(5, 6, 0)))
+
+	sla := &timeinterval.SLA{
+		Window:      timeinterval.NewTimeInterval(at(1, 0, 0), at(11, 0, 0)),
+		Target:      99,
+		Maintenance: maintenance,
+	}
+
+	r := sla.Report(outages)
+	assert.Equal(t, r.Measured, time.Hour*236)
+	assert.Equal(t, r.Downtime, time.Minute*150)
+	assert.InDelta(t, r.Availability, (236-2.5)/236*100, 1e-9)
+	assert.Equal(t, r.Budget, time.Duration(float64(time.Hour*236)*0.01))
+	assert.Equal(t, r.BudgetRemaining, r.Budget-time.Minute*150)
+	assert.InDelta(t, r.BudgetConsumed, 150/141.6*100, 1e-9)
+	assert.Equal(t, r.Met, false)
+
+	// business hours only
+	sla.Calendar = timeinterval.NewTimeOfDayInterval(
+		timeinterval.NewTimeOfDay(9, 0, 0, 0),
+		timeinterval.NewTimeOfDay(17, 0, 0, 0),
+	).TimeIntervals(timeinterval.NewDateInterval(timeinterval.NewDate(year, month, 1), timeinterval.NewDate(year, month, 10)), time.UTC)
+
+	r = sla.Report(outages)
+	assert.Equal(t, r.Measured, time.Hour*80)
+	assert.Equal(t, r.Downtime, time.Minute*90)
+	assert.Equal(t, r.Budget, time.Minute*48)
+	assert.Equal(t, r.BudgetRemaining, -time.Minute*42)
+	assert.InDelta(t, r.BudgetConsumed, 187.5, 1e-9)
+	assert.Equal(t, r.Met, false)
+
+	sla.Target = 98
+	assert.Equal(t, sla.Report(outages).Met, true)
+
+	periods := timeinterval.NewTimeIntervalSet()
+	periods.Add(
+		timeinterval.NewDate(year, month, 2).TimeInterval(time.UTC),
+		timeinterval.NewDate(year, month, 3).TimeInterval(time.UTC),
+		timeinterval.NewDate(year, month, 20).TimeInterval(time.UTC), // outside the window
+	)
+
+	reports := sla.Breakdown(outages, periods)
+	assert.Equal(t, len(reports), 3)
+
+	assert.Equal(t, reports[0].Measured, time.Hour*8)
+	assert.Equal(t, reports[0].Downtime, time.Duration(0))
+	assert.Equal(t, reports[0].Availability, 100.0)
+	assert.Equal(t, reports[0].Met, true)
+
+	assert.Equal(t, reports[1].Downtime, time.Minute*90)
+	assert.InDelta(t, reports[1].Availability, (480-90)/480.0*100, 1e-9)
+	assert.Equal(t, reports[1].Met, false)
+
+	assert.Equal(t, reports[2].Window.IsEmpty(), true)
+	assert.Equal(t, reports[2].Measured, time.Duration(0))
+	assert.Equal(t, reports[2].Availability, 100.0)
+
+	// no budget
+	sla.Target = 100
+	r = sla.Report(outages)
+	assert.Equal(t, r.Budget, time.Duration(0))
+	assert.Equal(t, math.IsInf(r.BudgetConsumed, 1), true)
+
+	sla.Target = 101
+	assert.Panics(t, func() { sla.Report(outages) })
+	assert.Panics(t, func() { (&timeinterval.SLA{}).Report(outages) })
+}
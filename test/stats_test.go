@@ -0,0 +1,67 @@
+package timeinterval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestTimeIntervalSetStats(t *testing.T) {
+	at := func(hour, min int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, day, hour, min, 0, 0)
+	}
+	interval := func(h1, m1, h2, m2 int) *timeinterval.TimeInterval {
+		return timeinterval.NewTimeInterval(at(h1, m1), at(h2, m2))
+	}
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(
+		interval(1, 0, 1, 10),
+		interval(1, 5, 1, 35), // overlapping
+		interval(3, 0, 3, 5),
+		interval(4, 0, 4, 0),
+		interval(6, 0, 7, 0),
+		timeinterval.NewEmptyTimeInterval(),
+	)
+
+	bounds := interval(0, 0, 12, 0)
+	stats := tis.Stats(bounds, []time.Duration{time.Minute * 10, time.Minute * 30})
+
+	// before Cleanup
+	assert.Equal(t, stats.Count, 5)
+	assert.Equal(t, stats.Total, time.Minute*105)
+	assert.Equal(t, stats.Min, time.Duration(0))
+	assert.Equal(t, stats.Max, time.Hour)
+	assert.Equal(t, stats.Mean, time.Minute*21)
+	assert.Equal(t, stats.Median, time.Minute*10)
+	assert.Equal(t, stats.Histogram, []int{2, 1, 2})
+
+	assert.Equal(t, stats.Percentile(0), time.Duration(0))
+	assert.Equal(t, stats.Percentile(100), time.Hour)
+	assert.Equal(t, stats.Percentile(75), time.Minute*30)
+	assert.Equal(t, stats.Percentile(87.5), time.Minute*45)
+
+	// after Cleanup
+	assert.Equal(t, stats.Covered, time.Minute*100)
+	assert.InDelta(t, stats.Coverage, 100.0/720, 1e-9)
+	assert.Equal(t, stats.LongestRun, interval(6, 0, 7, 0))
+
+	// only the parts within bounds are covered
+	stats = tis.Stats(interval(1, 30, 6, 30), nil)
+	assert.Equal(t, stats.Covered, time.Minute*40)
+	assert.Equal(t, stats.Histogram, []int{5})
+
+	stats = timeinterval.NewTimeIntervalSet().Stats(bounds, nil)
+	assert.Equal(t, stats.Count, 0)
+	assert.Equal(t, stats.Mean, time.Duration(0))
+	assert.Equal(t, stats.Coverage, 0.0)
+	assert.Equal(t, stats.LongestRun.IsEmpty(), true)
+	assert.Equal(t, stats.Percentile(50), time.Duration(0))
+
+	assert.Panics(t, func() { tis.Stats(nil, nil) })
+	assert.Panics(t, func() { tis.Stats(bounds, []time.Duration{time.Hour, time.Minute}) })
+	assert.Panics(t, func() { stats.Percentile(101) })
+}
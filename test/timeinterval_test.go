@@ -407,3 +407,231 @@ func TestTimeIntervalSetSort(t *testing.T) {
 	assert.Equal(t, tis.Elements()[7].Equal(ti34), true)
 	assert.Equal(t, tis.Elements()[8].Equal(ti45), true)
 }
+
+func TestTimeIntervalSetUnion(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+	t5 := timeinterval.NewTimePoint(year, month, day, 19, 4, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti23 := timeinterval.NewTimeInterval(t2, t3)
+	ti45 := timeinterval.NewTimeInterval(t4, t5)
+
+	tis1 := timeinterval.NewTimeIntervalSet()
+	tis1.Add(ti45, ti12)
+	tis2 := timeinterval.NewTimeIntervalSet()
+	tis2.Add(ti23)
+
+	tis := tis1.Union(tis2)
+
+	assert.Equal(t, len(tis1.Elements()), 2)
+	assert.Equal(t, len(tis.Elements()), 2)
+	assert.Equal(t, tis.Elements()[0].Equal(ti13), true)
+	assert.Equal(t, tis.Elements()[1].Equal(ti45), true)
+}
+
+func TestTimeIntervalSetIntersect(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+	t5 := timeinterval.NewTimePoint(year, month, day, 19, 4, 0, 0)
+	t6 := timeinterval.NewTimePoint(year, month, day, 19, 5, 0, 0)
+
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti16 := timeinterval.NewTimeInterval(t1, t6)
+	ti24 := timeinterval.NewTimeInterval(t2, t4)
+	ti56 := timeinterval.NewTimeInterval(t5, t6)
+	ti44 := timeinterval.NewTimeInterval(t4, t4)
+
+	tis1 := timeinterval.NewTimeIntervalSet()
+	tis1.Add(ti56, ti13)
+	tis2 := timeinterval.NewTimeIntervalSet()
+	tis2.Add(ti24, ti16, ti44)
+
+	tis := tis1.Intersect(tis2)
+	assert.Equal(t, len(tis.Elements()), 2)
+	assert.Equal(t, tis.Elements()[0].Equal(ti13), true)
+	assert.Equal(t, tis.Elements()[1].Equal(ti56), true)
+
+	tis2.Clear()
+	tis2.Add(ti24)
+
+	tis = tis1.Intersect(tis2)
+	assert.Equal(t, len(tis.Elements()), 1)
+	assert.Equal(t, tis.Elements()[0].Equal(timeinterval.NewTimeInterval(t2, t3)), true)
+
+	assert.Equal(t, len(tis1.Intersect(timeinterval.NewTimeIntervalSet()).Elements()), 0)
+}
+
+func TestTimeIntervalSetSubtract(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+	t5 := timeinterval.NewTimePoint(year, month, day, 19, 4, 0, 0)
+	t6 := timeinterval.NewTimePoint(year, month, day, 19, 5, 0, 0)
+	t7 := timeinterval.NewTimePoint(year, month, day, 19, 6, 0, 0)
+
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti47 := timeinterval.NewTimeInterval(t4, t7)
+	ti25 := timeinterval.NewTimeInterval(t2, t5)
+	ti66 := timeinterval.NewTimeInterval(t6, t6)
+	ti67 := timeinterval.NewTimeInterval(t6, t7)
+
+	tis1 := timeinterval.NewTimeIntervalSet()
+	tis1.Add(ti47, ti13)
+	tis2 := timeinterval.NewTimeIntervalSet()
+	tis2.Add(ti66, ti25, ti67)
+
+	tis := tis1.Subtract(tis2)
+	assert.Equal(t, len(tis.Elements()), 2)
+	assert.Equal(t, tis.Elements()[0].Equal(timeinterval.NewTimeInterval(t1, t2)), true)
+	assert.Equal(t, tis.Elements()[1].Equal(timeinterval.NewTimeInterval(t5, t6)), true)
+
+	tis = tis1.Subtract(tis1)
+	assert.Equal(t, len(tis.Elements()), 0)
+
+	tis = tis1.Subtract(timeinterval.NewTimeIntervalSet())
+	assert.Equal(t, len(tis.Elements()), 2)
+	assert.Equal(t, tis.Duration(), time.Minute*5)
+}
+
+func TestTimeIntervalSetGaps(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+	t5 := timeinterval.NewTimePoint(year, month, day, 19, 4, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti23 := timeinterval.NewTimeInterval(t2, t3)
+	ti45 := timeinterval.NewTimeInterval(t4, t5)
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(ti45, ti23, ti12)
+
+	gaps := tis.Gaps()
+	assert.Equal(t, len(gaps.Elements()), 1)
+	assert.Equal(t, gaps.Elements()[0].Equal(timeinterval.NewTimeInterval(t3, t4)), true)
+}
+
+func TestTimeIntervalEmpty(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+
+	ti11 := timeinterval.NewTimeInterval(t1, t1)
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	empty := timeinterval.NewEmptyTimeInterval()
+
+	assert.Equal(t, empty.IsEmpty(), true)
+	assert.Equal(t, (&timeinterval.TimeInterval{}).IsEmpty(), true)
+	assert.Equal(t, ti11.IsEmpty(), false)
+	assert.Equal(t, empty.IsZeroDuration(), false)
+	assert.Equal(t, ti11.IsZeroDuration(), true)
+	assert.Equal(t, empty.Duration(), time.Duration(0))
+	assert.Nil(t, empty.Start())
+	assert.Equal(t, empty.String(), "∅")
+
+	assert.Equal(t, empty.Equal(timeinterval.NewEmptyTimeInterval()), true)
+	assert.Equal(t, empty.Equal(ti11), false)
+	assert.Equal(t, ti11.Equal(empty), false)
+
+	assert.Equal(t, empty.Has(t1), false)
+
+	assert.Equal(t, ti11.Covers(empty), true)
+	assert.Equal(t, empty.Covers(empty), true)
+	assert.Equal(t, empty.Covers(ti11), false)
+
+	assert.Equal(t, empty.Intersects(ti12), false)
+	assert.Equal(t, ti12.Intersects(empty), false)
+
+	assert.Equal(t, empty.Mergeable(ti12), true)
+	assert.Equal(t, empty.Merge(ti12), ti12)
+	assert.Equal(t, ti12.Merge(empty), ti12)
+
+	assert.Equal(t, len(empty.Subtract(ti12).Elements()), 0)
+	assert.Equal(t, ti12.Subtract(empty).Elements(), []*timeinterval.TimeInterval{ti12})
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(ti12, empty, ti11)
+	tis.Sort()
+	assert.Equal(t, tis.Elements(), []*timeinterval.TimeInterval{empty, ti11, ti12})
+
+	// the empty elements are removed even without removeZeroDuration
+	tis.Cleanup(false)
+	assert.Equal(t, tis.Elements(), []*timeinterval.TimeInterval{ti12})
+
+	tis2 := timeinterval.NewTimeIntervalSet()
+	tis2.Add(empty)
+	assert.Equal(t, len(tis2.Union(tis2).Elements()), 0)
+	assert.Equal(t, tis.Union(tis2).Elements(), []*timeinterval.TimeInterval{ti12})
+	assert.Equal(t, len(tis.Intersect(tis2).Elements()), 0)
+	assert.Equal(t, tis.Subtract(tis2).Elements(), []*timeinterval.TimeInterval{ti12})
+	assert.Equal(t, len(tis2.Gaps().Elements()), 0)
+}
+
+func TestTimeIntervalIntersection(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+
+	ti13 := timeinterval.NewTimeInterval(t1, t3)
+	ti24 := timeinterval.NewTimeInterval(t2, t4)
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti34 := timeinterval.NewTimeInterval(t3, t4)
+	ti22 := timeinterval.NewTimeInterval(t2, t2)
+	ti33 := timeinterval.NewTimeInterval(t3, t3)
+	empty := timeinterval.NewEmptyTimeInterval()
+
+	assert.Equal(t, ti13.Intersection(ti24), timeinterval.NewTimeInterval(t2, t3))
+	assert.Equal(t, ti24.Intersection(ti13), timeinterval.NewTimeInterval(t2, t3))
+	assert.Equal(t, ti13.OverlapDuration(ti24), time.Minute)
+
+	// touching
+	assert.Equal(t, ti12.Intersection(ti34).IsEmpty(), true)
+	assert.Equal(t, ti13.Intersection(ti34).IsEmpty(), true)
+	assert.Equal(t, ti13.OverlapDuration(ti34), time.Duration(0))
+
+	// zero duration
+	assert.Equal(t, ti13.Intersection(ti22), ti22)
+	assert.Equal(t, ti33.Intersection(ti13), ti33)
+	assert.Equal(t, ti12.Intersection(ti33).IsEmpty(), true)
+	assert.Equal(t, ti22.Intersection(ti22), ti22)
+	assert.Equal(t, timeinterval.Intersection(ti22, ti22), ti22)
+
+	assert.Equal(t, ti13.Intersection(empty).IsEmpty(), true)
+	assert.Equal(t, empty.Intersection(ti13).IsEmpty(), true)
+
+	assert.Equal(t, timeinterval.Intersection(ti13), ti13)
+	assert.Equal(t, timeinterval.Intersection(ti13, ti24, timeinterval.NewTimeInterval(t1, t4)), timeinterval.NewTimeInterval(t2, t3))
+	assert.Equal(t, timeinterval.Intersection(ti13, ti24, ti12).IsEmpty(), true)
+	assert.Panics(t, func() { timeinterval.Intersection() })
+}
+
+func TestTimeIntervalSpan(t *testing.T) {
+	t1 := timeinterval.NewTimePoint(year, month, day, 19, 0, 0, 0)
+	t2 := timeinterval.NewTimePoint(year, month, day, 19, 1, 0, 0)
+	t3 := timeinterval.NewTimePoint(year, month, day, 19, 2, 0, 0)
+	t4 := timeinterval.NewTimePoint(year, month, day, 19, 3, 0, 0)
+
+	ti12 := timeinterval.NewTimeInterval(t1, t2)
+	ti34 := timeinterval.NewTimeInterval(t3, t4)
+	ti22 := timeinterval.NewTimeInterval(t2, t2)
+	empty := timeinterval.NewEmptyTimeInterval()
+
+	// not mergeable
+	assert.Equal(t, timeinterval.Span(ti34, ti12), timeinterval.NewTimeInterval(t1, t4))
+	assert.Equal(t, timeinterval.Span(empty, ti22), ti22)
+	assert.Equal(t, timeinterval.Span(empty).IsEmpty(), true)
+	assert.Equal(t, timeinterval.Span().IsEmpty(), true)
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(ti22, ti34)
+	assert.Equal(t, tis.Span(), timeinterval.NewTimeInterval(t2, t4))
+	assert.Equal(t, timeinterval.NewTimeIntervalSet().Span().IsEmpty(), true)
+}
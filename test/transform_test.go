@@ -0,0 +1,111 @@
+package timeinterval_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iloy/timeinterval"
+)
+
+func TestTimeIntervalTransform(t *testing.T) {
+	at := func(hour int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, day, hour, 0, 0, 0)
+	}
+	interval := func(h1, h2 int) *timeinterval.TimeInterval {
+		return timeinterval.NewTimeInterval(at(h1), at(h2))
+	}
+
+	ti := interval(10, 14)
+
+	assert.Equal(t, ti.Expand(time.Hour, time.Hour*2), interval(9, 16))
+	assert.Equal(t, ti.Expand(time.Hour*11, 0).Start().Day(), day-1)
+
+	assert.Equal(t, ti.Shrink(time.Hour, time.Hour*2), interval(11, 12))
+	assert.Equal(t, ti.Shrink(time.Hour*2, time.Hour*2).IsEmpty(), true)
+	assert.Equal(t, ti.Shrink(time.Hour*3, time.Hour*3).IsEmpty(), true)
+	assert.Equal(t, ti.Shrink(time.Hour*5, 0).IsEmpty(), true)
+	assert.Equal(t, interval(12, 12).Shrink(0, 0), interval(12, 12))
+
+	assert.Equal(t, ti.Shift(time.Hour*3), interval(13, 17))
+	assert.Equal(t, ti.Shift(-time.Hour*3), interval(7, 11))
+
+	assert.Equal(t, ti.Clamp(interval(12, 20)), interval(12, 14))
+	assert.Equal(t, ti.Clamp(interval(11, 12)), interval(11, 12))
+	assert.Equal(t, ti.Clamp(interval(16, 20)).IsEmpty(), true)
+	assert.Equal(t, ti.Clamp(interval(0, 10)).IsEmpty(), true)
+	assert.Equal(t, interval(12, 12).Clamp(interval(12, 20)), interval(12, 12))
+	assert.Equal(t, interval(1, 3).Clamp(interval(3, 3)), interval(3, 3))
+	assert.Equal(t, interval(12, 12).Clamp(interval(0, 8)).IsEmpty(), true)
+
+	assert.Equal(t, ti.Scale(2, at(10)), interval(10, 18))
+	assert.Equal(t, ti.Scale(0.5, at(12)), interval(11, 13))
+	assert.Equal(t, ti.Scale(0, at(0)), interval(0, 0))
+
+	empty := timeinterval.NewEmptyTimeInterval()
+	assert.Equal(t, empty.Expand(time.Hour, time.Hour).IsEmpty(), true)
+	assert.Equal(t, empty.Shrink(0, 0).IsEmpty(), true)
+	assert.Equal(t, empty.Shift(time.Hour).IsEmpty(), true)
+	assert.Equal(t, empty.Clamp(ti).IsEmpty(), true)
+	assert.Equal(t, empty.Scale(2, at(0)).IsEmpty(), true)
+
+	// leap seconds are counted in TimeScaleUTC
+	utc := timeinterval.NewTimeInterval(
+		timeinterval.NewTimePointUTC(2016, 12, 31, 23, 59, 59, 0),
+		timeinterval.NewTimePointUTC(2017, 1, 1, 0, 0, 0, 0),
+	)
+	assert.Equal(t, utc.Shift(time.Second).Start().IsLeapSecond(), true)
+	assert.Equal(t, utc.Expand(0, time.Second).Duration(), time.Second*3)
+
+	assert.Panics(t, func() { ti.Expand(-time.Hour, 0) })
+	assert.Panics(t, func() { ti.Shrink(0, -time.Hour) })
+	assert.Panics(t, func() { ti.Scale(-1, at(0)) })
+	assert.Panics(t, func() { ti.Scale(1, nil) })
+}
+
+func TestTimeIntervalSetTransform(t *testing.T) {
+	at := func(hour int) *timeinterval.TimePoint {
+		return timeinterval.NewTimePoint(year, month, day, hour, 0, 0, 0)
+	}
+	interval := func(h1, h2 int) *timeinterval.TimeInterval {
+		return timeinterval.NewTimeInterval(at(h1), at(h2))
+	}
+
+	tis := timeinterval.NewTimeIntervalSet()
+	tis.Add(interval(8, 9), interval(1, 3), interval(5, 6), interval(12, 12))
+
+	// merged
+	assert.Equal(t, tis.Expand(time.Hour, 0).Elements(), []*timeinterval.TimeInterval{
+		interval(0, 3), interval(4, 6), interval(7, 9), interval(11, 12),
+	})
+	assert.Equal(t, tis.Expand(time.Hour, time.Hour).Elements(), []*timeinterval.TimeInterval{
+		interval(0, 10), interval(11, 13),
+	})
+
+	// collapsed elements are removed, but not the zero duration elements
+	assert.Equal(t, tis.Shrink(time.Minute*30, time.Minute*30).Elements(), []*timeinterval.TimeInterval{
+		timeinterval.NewTimeInterval(timeinterval.NewTimePoint(year, month, day, 1, 30, 0, 0), timeinterval.NewTimePoint(year, month, day, 2, 30, 0, 0)),
+	})
+	assert.Equal(t, tis.Shrink(0, 0).Elements(), []*timeinterval.TimeInterval{
+		interval(1, 3), interval(5, 6), interval(8, 9), interval(12, 12),
+	})
+
+	assert.Equal(t, tis.Shift(time.Hour).Elements(), []*timeinterval.TimeInterval{
+		interval(2, 4), interval(6, 7), interval(9, 10), interval(13, 13),
+	})
+
+	assert.Equal(t, tis.Clamp(interval(2, 8)).Elements(), []*timeinterval.TimeInterval{
+		interval(2, 3), interval(5, 6),
+	})
+	assert.Equal(t, tis.Clamp(interval(0, 12)).Elements(), []*timeinterval.TimeInterval{
+		interval(1, 3), interval(5, 6), interval(8, 9), interval(12, 12),
+	})
+
+	assert.Equal(t, tis.Scale(2, at(0)).Elements(), []*timeinterval.TimeInterval{
+		interval(2, 6), interval(10, 12), interval(16, 18), interval(24, 24),
+	})
+
+	// tis is not modified
+	assert.Equal(t, tis.Elements()[0], interval(8, 9))
+}
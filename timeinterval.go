@@ -26,6 +26,9 @@ type TimePoint struct {
 	nanosecond int
 
 	t time.Time
+
+	scale TimeScale
+	leap  bool // 23:59:60 in TimeScaleUTC
 }
 
 func (tp *TimePoint) Year() int {
@@ -78,6 +81,55 @@ func NewTimePoint(year, month, day, hour, minute, sec, nsec int) *TimePoint {
 	return ret
 }
 
+// FromTime returns the TimePoint of the instant t, in UTC
+func FromTime(t time.Time) *TimePoint {
+	t = t.UTC()
+
+	return NewTimePoint(t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+}
+
+// FromUnix returns the TimePoint of sec seconds and nsec nanoseconds since January 1, 1970 UTC.
+// nsec outside [0, 999999999] is normalized as time.Unix() does.
+func FromUnix(sec, nsec int64) *TimePoint {
+	return FromTime(time.Unix(sec, nsec))
+}
+
+func FromUnixMilli(msec int64) *TimePoint {
+	return FromTime(time.UnixMilli(msec))
+}
+
+func FromUnixMicro(usec int64) *TimePoint {
+	return FromTime(time.UnixMicro(usec))
+}
+
+func FromUnixNano(nsec int64) *TimePoint {
+	return FromTime(time.Unix(0, nsec))
+}
+
+// ToTime returns tp as time.Time in UTC.
+// A leap second is returned as the next midnight, since time.Time cannot represent it.
+func (tp *TimePoint) ToTime() time.Time {
+	return tp.t
+}
+
+func (tp *TimePoint) Unix() int64 {
+	return tp.t.Unix()
+}
+
+func (tp *TimePoint) UnixMilli() int64 {
+	return tp.t.UnixMilli()
+}
+
+func (tp *TimePoint) UnixMicro() int64 {
+	return tp.t.UnixMicro()
+}
+
+// UnixNano is undefined if tp cannot be represented by int64 nanoseconds
+// (before 1678 or after 2262) as time.Time.UnixNano()
+func (tp *TimePoint) UnixNano() int64 {
+	return tp.t.UnixNano()
+}
+
 func (tp *TimePoint) Copy() *TimePoint {
 	// TimePoint is immutable
 	return tp
@@ -85,6 +137,10 @@ func (tp *TimePoint) Copy() *TimePoint {
 
 func (tp *TimePoint) Compare(tp2 *TimePoint) CompareResult {
 	v := tp.t.Compare(tp2.t)
+	if v == 0 && (tp.leap || tp2.leap) {
+		v = compareLeap(tp, tp2)
+	}
+
 	switch v {
 	case -1:
 		return Before
@@ -109,8 +165,13 @@ func (tp *TimePoint) After(tp2 *TimePoint) bool {
 	return tp.Compare(tp2) == After
 }
 
-// sub returns the duration tp - tp2
+// sub returns the duration tp - tp2.
+// Leap seconds are counted if any of them is in TimeScaleUTC.
 func (tp *TimePoint) sub(tp2 *TimePoint) time.Duration {
+	if tp.scale == TimeScaleUTC || tp2.scale == TimeScaleUTC {
+		return tp.tai().Sub(tp2.tai())
+	}
+
 	return tp.t.Sub(tp2.t)
 }
 
@@ -153,17 +214,22 @@ func TimePointMin(tp ...*TimePoint) *TimePoint {
 }
 
 // TimeInterval
+//
+// The empty TimeInterval, which is also the zero value, has no TimePoints at all.
+// It is distinct from a zero duration TimeInterval, which has one TimePoint.
 type TimeInterval struct {
-	start *TimePoint
-	end   *TimePoint
+	start *TimePoint // nil if empty
+	end   *TimePoint // nil if empty
 
 	duration time.Duration
 }
 
+// Start returns nil if ti is empty
 func (ti *TimeInterval) Start() *TimePoint {
 	return ti.start
 }
 
+// End returns nil if ti is empty
 func (ti *TimeInterval) End() *TimePoint {
 	return ti.end
 }
@@ -172,8 +238,13 @@ func (ti *TimeInterval) Duration() time.Duration {
 	return ti.duration
 }
 
+// IsZeroDuration is false for the empty TimeInterval
 func (ti *TimeInterval) IsZeroDuration() bool {
-	return ti.Duration() == time.Duration(0)
+	return !ti.IsEmpty() && ti.Duration() == time.Duration(0)
+}
+
+func (ti *TimeInterval) IsEmpty() bool {
+	return ti.start == nil
 }
 
 func NewTimeInterval(start, end *TimePoint) *TimeInterval {
@@ -195,20 +266,46 @@ func NewTimeInterval(start, end *TimePoint) *TimeInterval {
 	return ret
 }
 
+// NewEmptyTimeInterval returns the empty TimeInterval,
+// e.g. the intersection of TimeIntervals not overlapping each other
+func NewEmptyTimeInterval() *TimeInterval {
+	ret := &TimeInterval{}
+
+	return ret
+}
+
 func (ti *TimeInterval) Copy() *TimeInterval {
 	// TimeInterval is immutable
 	return ti
 }
 
+// Equal is true for two empty TimeIntervals
 func (ti *TimeInterval) Equal(ti2 *TimeInterval) bool {
+	if ti.IsEmpty() || ti2.IsEmpty() {
+		return ti.IsEmpty() && ti2.IsEmpty()
+	}
+
 	return ti.start.Equal(ti2.start) && ti.end.Equal(ti2.end)
 }
 
+// Has is false for the empty TimeInterval
 func (ti *TimeInterval) Has(tp *TimePoint) bool {
+	if ti.IsEmpty() {
+		return false
+	}
+
 	return (!tp.Before(ti.start)) && (!tp.After(ti.end))
 }
 
+// Covers is true for the empty ti2, as the empty set is a subset of any set
 func (ti *TimeInterval) Covers(ti2 *TimeInterval) bool {
+	if ti2.IsEmpty() {
+		return true
+	}
+	if ti.IsEmpty() {
+		return false
+	}
+
 	return (!ti.start.After(ti2.start)) && (!ti.end.Before(ti2.end))
 }
 
@@ -219,14 +316,94 @@ func (ti *TimeInterval) Intersects(ti2 *TimeInterval) bool {
 	//   ||
 	//   (ti.end.Equal(ti2.start) || ti.end.Before(ti2.start))
 	// )
+	if ti.IsEmpty() || ti2.IsEmpty() {
+		return false
+	}
+
 	return (ti.start.Before(ti2.end)) && (ti.end.After(ti2.start))
 }
 
+// Intersection returns the overlap of ti and ti2, or the empty TimeInterval if they do not intersect.
+// A zero duration TimeInterval is the overlap with a TimeInterval having it, even at the ends.
+func (ti *TimeInterval) Intersection(ti2 *TimeInterval) *TimeInterval {
+	if ti.IsZeroDuration() && ti2.Has(ti.start) {
+		return ti
+	}
+	if ti2.IsZeroDuration() && ti.Has(ti2.start) {
+		return ti2
+	}
+
+	if !ti.Intersects(ti2) {
+		return NewEmptyTimeInterval()
+	}
+
+	ret := NewTimeInterval(
+		TimePointMax(ti.start, ti2.start),
+		TimePointMin(ti.end, ti2.end),
+	)
+
+	return ret
+}
+
+// OverlapDuration returns the duration of Intersection()
+func (ti *TimeInterval) OverlapDuration(ti2 *TimeInterval) time.Duration {
+	return ti.Intersection(ti2).Duration()
+}
+
+// Intersection returns the overlap of all of ti
+func Intersection(ti ...*TimeInterval) *TimeInterval {
+	if len(ti) == 0 {
+		panic("no argument")
+	}
+
+	ret := ti[0]
+
+	for _, v := range ti[1:] {
+		ret = ret.Intersection(v)
+	}
+
+	return ret
+}
+
+// Span returns the smallest TimeInterval covering all of ti, even if they are not mergeable.
+// The empty TimeIntervals are ignored, and the result is empty if there are no others.
+func Span(ti ...*TimeInterval) *TimeInterval {
+	var start, end *TimePoint
+
+	for _, v := range ti {
+		if v.IsEmpty() {
+			continue
+		}
+
+		if start == nil {
+			start, end = v.start, v.end
+			continue
+		}
+
+		start = TimePointMin(start, v.start)
+		end = TimePointMax(end, v.end)
+	}
+
+	if start == nil {
+		return NewEmptyTimeInterval()
+	}
+
+	return NewTimeInterval(start, end)
+}
+
 func (ti *TimeInterval) Merge(ti2 *TimeInterval) *TimeInterval {
 	if !ti.Mergeable(ti2) {
 		panic(fmt.Sprintf("not mergeable: %v, %v", ti, ti2))
 	}
 
+	// the empty TimeInterval is mergeable with anything, and changes nothing
+	if ti.IsEmpty() {
+		return ti2
+	}
+	if ti2.IsEmpty() {
+		return ti
+	}
+
 	ret := NewTimeInterval(
 		TimePointMin(ti.start, ti2.start),
 		TimePointMax(ti.end, ti2.end),
@@ -241,6 +418,10 @@ func (ti *TimeInterval) Mergeable(ti2 *TimeInterval) bool {
 	// 두 개의 TimeInterval 이 바로 붙어 있는 경우에는
 	// Intersects() 는 false 이지만
 	// Mergeable() 은 true
+	if ti.IsEmpty() || ti2.IsEmpty() {
+		return true
+	}
+
 	if (ti.start.After(ti2.end)) || (ti.end.Before(ti2.start)) {
 		return false
 	}
@@ -251,6 +432,10 @@ func (ti *TimeInterval) Mergeable(ti2 *TimeInterval) bool {
 func (ti *TimeInterval) Subtract(ti2 *TimeInterval) *TimeIntervalSet {
 	ret := NewTimeIntervalSet()
 
+	if ti.IsEmpty() {
+		return ret
+	}
+
 	if ti2.IsZeroDuration() || !ti.Intersects(ti2) {
 		ret.Add(ti)
 		return ret
@@ -276,11 +461,12 @@ func (tis *TimeIntervalSet) Elements() []*TimeInterval {
 	return tis.elements
 }
 
+// Duration saturates as TimeInterval.Duration() does. See also DurationChecked() and WideDuration()
 func (tis *TimeIntervalSet) Duration() time.Duration {
 	ret := time.Duration(0)
 
 	for _, ti := range tis.elements {
-		ret += ti.Duration()
+		ret = addSaturated(ret, ti.Duration())
 	}
 
 	return ret
@@ -318,59 +504,157 @@ func (tis *TimeIntervalSet) Merge(tis2 ...*TimeIntervalSet) {
 	}
 }
 
+// Cleanup merges the elements mergeable and sorts them, in O(n log n) time.
+// The empty elements are always removed.
 func (tis *TimeIntervalSet) Cleanup(removeZeroDuration bool) {
-	if removeZeroDuration {
-	cleanupStart0:
-		l := len(tis.elements)
-
-		for i := 0; i < l; i++ {
-			elem := tis.elements[i]
-			if elem.IsZeroDuration() {
-				tis.elements = append(tis.elements[:i], tis.elements[i+1:]...)
-				goto cleanupStart0
+	tis.elements = slices.DeleteFunc(tis.elements, func(ti *TimeInterval) bool {
+		return ti.IsEmpty() || removeZeroDuration && ti.IsZeroDuration()
+	})
+
+	tis.Sort()
+
+	// merge the neighbors if mergeable. As the elements are sorted by start,
+	// an element mergeable with any of the merged ones is mergeable with the last one.
+	n := 0
+	for _, v := range tis.elements {
+		if n > 0 && tis.elements[n-1].Mergeable(v) {
+			if v.end.After(tis.elements[n-1].end) {
+				tis.elements[n-1] = tis.elements[n-1].Merge(v)
 			}
+			continue
 		}
-	}
 
-	// merge two TimeIntervals if mergeable
-	{
-	cleanupStart1:
-		l := len(tis.elements)
+		tis.elements[n] = v
+		n++
+	}
 
-		for i := 0; i < l; i++ {
-			for j := i + 1; j < l; j++ {
-				first := tis.elements[i]
-				second := tis.elements[j]
+	clear(tis.elements[n:])
+	tis.elements = tis.elements[:n]
+}
 
-				if first.Mergeable(second) {
-					tis.elements[i] = first.Merge(second)
-					tis.elements = append(tis.elements[:j], tis.elements[j+1:]...)
+func (tis *TimeIntervalSet) Sort() {
+	slices.SortFunc(tis.elements, compareTimeInterval)
+}
 
-					goto cleanupStart1
-				}
-			}
+// compareTimeInterval orders TimeIntervals by start, then by end.
+// The empty TimeIntervals come first.
+func compareTimeInterval(a, b *TimeInterval) int {
+	if a.IsEmpty() || b.IsEmpty() {
+		switch {
+		case a.IsEmpty() && b.IsEmpty():
+			return 0
+		case a.IsEmpty():
+			return -1
+		default:
+			return 1
 		}
 	}
+	if a.Start().Before(b.Start()) {
+		return -1
+	}
+	if a.Start().After(b.Start()) {
+		return 1
+	}
+	if a.End().Before(b.End()) {
+		return -1
+	}
+	if a.End().After(b.End()) {
+		return 1
+	}
+	return 0
+}
 
-	tis.Sort()
+// cleanedUp returns a copy of tis cleaned up with removeZeroDuration
+func (tis *TimeIntervalSet) cleanedUp() *TimeIntervalSet {
+	ret := tis.Copy()
+
+	ret.Cleanup(true)
+
+	return ret
 }
 
-func (tis *TimeIntervalSet) Sort() {
-	slices.SortFunc(tis.elements, func(a, b *TimeInterval) int {
-		if a.Start().Before(b.Start()) {
-			return -1
+// Union returns a new TimeIntervalSet covering tis and all of tis2.
+// Unlike Merge(), tis is not modified and the result is cleaned up.
+func (tis *TimeIntervalSet) Union(tis2 ...*TimeIntervalSet) *TimeIntervalSet {
+	ret := tis.Copy()
+
+	ret.Merge(tis2...)
+	ret.Cleanup(true)
+
+	return ret
+}
+
+// Intersect returns a new, cleaned up TimeIntervalSet covering
+// the parts covered by both tis and tis2
+func (tis *TimeIntervalSet) Intersect(tis2 *TimeIntervalSet) *TimeIntervalSet {
+	a := tis.cleanedUp().elements
+	b := tis2.cleanedUp().elements
+
+	ret := NewTimeIntervalSet()
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if a[i].Intersects(b[j]) {
+			ret.Add(a[i].Intersection(b[j]))
 		}
-		if a.Start().After(b.Start()) {
-			return 1
+
+		if a[i].end.Before(b[j].end) {
+			i++
+		} else {
+			j++
 		}
-		if a.End().Before(b.End()) {
-			return -1
+	}
+
+	return ret
+}
+
+// Subtract returns a new, cleaned up TimeIntervalSet covering
+// the parts covered by tis but not by tis2
+func (tis *TimeIntervalSet) Subtract(tis2 *TimeIntervalSet) *TimeIntervalSet {
+	a := tis.cleanedUp().elements
+	b := tis2.cleanedUp().elements
+
+	ret := NewTimeIntervalSet()
+
+	j := 0
+	for _, v := range a {
+		start := v.start
+
+		for j < len(b) && !b[j].end.After(start) {
+			j++
 		}
-		if a.End().After(b.End()) {
-			return 1
+
+		// b[k] may also cover the next element of a, so j is not advanced here
+		for k := j; k < len(b) && b[k].start.Before(v.end); k++ {
+			if b[k].start.After(start) {
+				ret.Add(NewTimeInterval(start, b[k].start))
+			}
+			start = TimePointMax(start, b[k].end)
 		}
-		return 0
-	})
+
+		if v.end.After(start) {
+			ret.Add(NewTimeInterval(start, v.end))
+		}
+	}
+
+	return ret
+}
+
+// Span returns the smallest TimeInterval covering all elements of tis, see Span()
+func (tis *TimeIntervalSet) Span() *TimeInterval {
+	return Span(tis.elements...)
+}
+
+// Gaps returns a new TimeIntervalSet covering the parts between the elements of tis
+func (tis *TimeIntervalSet) Gaps() *TimeIntervalSet {
+	a := tis.cleanedUp().elements
+
+	ret := NewTimeIntervalSet()
+
+	for i := 1; i < len(a); i++ {
+		ret.Add(NewTimeInterval(a[i-1].end, a[i].start))
+	}
+
+	return ret
 }
 
 /*
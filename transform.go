@@ -0,0 +1,149 @@
+package timeinterval
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// add returns tp + d. Leap seconds are counted in TimeScaleUTC as sub() does.
+func (tp *TimePoint) add(d time.Duration) *TimePoint {
+	if tp.scale == TimeScaleUTC {
+		return FromTAI(tp.TAI().Add(d))
+	}
+
+	return FromTime(tp.t.Add(d))
+}
+
+// Expand returns ti with the start moved earlier by before and the end moved later by after.
+// The empty TimeInterval stays empty.
+func (ti *TimeInterval) Expand(before, after time.Duration) *TimeInterval {
+	if before < 0 || after < 0 {
+		panic(fmt.Sprint("negative duration: ", before, ", ", after))
+	}
+
+	if ti.IsEmpty() {
+		return ti
+	}
+
+	return NewTimeInterval(ti.start.add(-before), ti.end.add(after))
+}
+
+// Shrink returns ti with the start moved later by before and the end moved earlier by after.
+// If they meet or cross, the result is the empty TimeInterval,
+// except that a zero duration ti is kept by Shrink(0, 0).
+func (ti *TimeInterval) Shrink(before, after time.Duration) *TimeInterval {
+	if before < 0 || after < 0 {
+		panic(fmt.Sprint("negative duration: ", before, ", ", after))
+	}
+
+	if ti.IsEmpty() {
+		return ti
+	}
+
+	start := ti.start.add(before)
+	end := ti.end.add(-after)
+
+	if end.Before(start) || !ti.IsZeroDuration() && end.Equal(start) {
+		return NewEmptyTimeInterval()
+	}
+
+	return NewTimeInterval(start, end)
+}
+
+// Shift returns ti moved by d, which can be negative
+func (ti *TimeInterval) Shift(d time.Duration) *TimeInterval {
+	if ti.IsEmpty() {
+		return ti
+	}
+
+	return NewTimeInterval(ti.start.add(d), ti.end.add(d))
+}
+
+// Clamp returns ti with both ends moved into bounds, which is the same as Intersection(bounds).
+// If nothing of ti is within bounds, the result is the empty TimeInterval.
+func (ti *TimeInterval) Clamp(bounds *TimeInterval) *TimeInterval {
+	return ti.Intersection(bounds)
+}
+
+// Scale returns ti with the distances of both ends from anchor multiplied by factor,
+// e.g. Scale(2, ti.Start()) doubles the duration keeping the start.
+// The distances saturate as Duration() does.
+func (ti *TimeInterval) Scale(factor float64, anchor *TimePoint) *TimeInterval {
+	if factor < 0 || math.IsNaN(factor) || math.IsInf(factor, 0) {
+		panic(fmt.Sprint("invalid factor: ", factor))
+	}
+	if anchor == nil {
+		panic("nil argument")
+	}
+
+	if ti.IsEmpty() {
+		return ti
+	}
+
+	scale := func(tp *TimePoint) *TimePoint {
+		d := float64(tp.sub(anchor)) * factor
+
+		switch {
+		case d >= math.MaxInt64:
+			return anchor.add(time.Duration(math.MaxInt64))
+		case d <= math.MinInt64:
+			return anchor.add(time.Duration(math.MinInt64))
+		default:
+			return anchor.add(time.Duration(d))
+		}
+	}
+
+	return NewTimeInterval(scale(ti.start), scale(ti.end))
+}
+
+// transformed returns a new TimeIntervalSet of the elements of tis transformed by f,
+// cleaned up without removeZeroDuration, which removes the empty results
+func (tis *TimeIntervalSet) transformed(f func(ti *TimeInterval) *TimeInterval) *TimeIntervalSet {
+	ret := NewTimeIntervalSet()
+
+	for _, v := range tis.elements {
+		ret.Add(f(v))
+	}
+
+	ret.Cleanup(false)
+
+	return ret
+}
+
+// Expand returns a new, cleaned up TimeIntervalSet of the elements expanded,
+// so the elements closer than before+after are merged
+func (tis *TimeIntervalSet) Expand(before, after time.Duration) *TimeIntervalSet {
+	return tis.transformed(func(ti *TimeInterval) *TimeInterval {
+		return ti.Expand(before, after)
+	})
+}
+
+// Shrink returns a new, cleaned up TimeIntervalSet of the elements shrunk, without the elements collapsed
+func (tis *TimeIntervalSet) Shrink(before, after time.Duration) *TimeIntervalSet {
+	return tis.transformed(func(ti *TimeInterval) *TimeInterval {
+		return ti.Shrink(before, after)
+	})
+}
+
+// Shift returns a new, cleaned up TimeIntervalSet of the elements shifted
+func (tis *TimeIntervalSet) Shift(d time.Duration) *TimeIntervalSet {
+	return tis.transformed(func(ti *TimeInterval) *TimeInterval {
+		return ti.Shift(d)
+	})
+}
+
+// Clamp returns a new, cleaned up TimeIntervalSet of the elements clamped, without the elements collapsed.
+// The zero duration elements within bounds are kept.
+func (tis *TimeIntervalSet) Clamp(bounds *TimeInterval) *TimeIntervalSet {
+	return tis.transformed(func(ti *TimeInterval) *TimeInterval {
+		return ti.Clamp(bounds)
+	})
+}
+
+// Scale returns a new, cleaned up TimeIntervalSet of the elements scaled around anchor
+func (tis *TimeIntervalSet) Scale(factor float64, anchor *TimePoint) *TimeIntervalSet {
+	return tis.transformed(func(ti *TimeInterval) *TimeInterval {
+		return ti.Scale(factor, anchor)
+	})
+}